@@ -0,0 +1,222 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// C family flags.
+const (
+	cRawStrings profileFlag = 1 << iota
+)
+
+// C is the profile for C: // and /* */ comments. Preprocessor directives,
+// e.g. #include and #define, are code. A // comment that ends with a
+// backslash continues on the next line. Strings are delimited by " and
+// character literals by '; both use backslash escapes and end at the EOL, so
+// the ' in #error don't is just a character. A ' within a number is
+// a digit separator, 1'000. The header name of an #include is not scanned
+// for comments.
+var C = &Profile{
+	Name:       "c",
+	Extensions: []string{".c", ".h"},
+	Hints:      []string{"#include <", "int main(", "printf(", "malloc(", "->", "NULL", "#define "},
+	lexText:    lexC,
+}
+
+// CPP is the profile for C++. It adds raw strings, R"delim(...)delim", to
+// the C profile.
+var CPP = &Profile{
+	Name:       "c++",
+	Aliases:    []string{"cpp", "cxx"},
+	Extensions: []string{".cc", ".cpp", ".cxx", ".c++", ".hh", ".hpp", ".hxx", ".h++"},
+	Hints:      []string{"#include <", "std::", "template <", "template<", "nullptr", "namespace ", "class "},
+	lexText:    lexC,
+	flags:      cRawStrings,
+}
+
+// lexC lexes C and C++.
+func lexC(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '/' && l.hasPrefix("//"):
+			l.emitText()
+			l.pos = l.splicedLineEnd(l.pos)
+			l.emit(tokenCPPComment)
+		case c == '/' && l.hasPrefix("/*"):
+			if !l.lexDelimited("/*", "*/", tokenCComment) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '"' || c == '\'':
+			if !l.lexCQuoted(c) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '#':
+			l.pos++
+			l.skipIncludeHeader()
+		case c >= '0' && c <= '9':
+			l.skipNumber()
+		case isIdentByte(c):
+			i := l.pos
+			for int(l.pos) < len(l.input) && isIdentByte(l.input[l.pos]) {
+				l.pos++
+			}
+			if !l.profile.has(cRawStrings) || !isRawStringPrefix(l.input[i:l.pos]) || !l.hasPrefix("\"") || l.rawStringDelim(l.pos) < 0 {
+				continue
+			}
+			l.pos = i
+			if !l.lexRawString() {
+				return l.errorf("unterminated raw string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// lexCQuoted consumes a string or character literal and emits it as quoted
+// text. A literal doesn't continue past an EOL that isn't spliced: if it
+// isn't closed on its line, e.g. the ' in #error don't, the quote is just a
+// character and only it is consumed. If the input ends before the literal is
+// closed, false is returned.
+func (l *lexer) lexCQuoted(quote byte) bool {
+	l.emitText()
+	for i := l.pos + 1; int(i) < len(l.input); i++ {
+		switch l.input[i] {
+		case '\\':
+			// an escaped EOL is a splice
+			i++
+			if int(i)+1 < len(l.input) && l.input[i] == cr && l.input[i+1] == nl {
+				i++
+			}
+		case quote:
+			l.pos = i + 1
+			l.emit(tokenQuotedText)
+			return true
+		case nl:
+			l.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// splicedLineEnd is lineEnd for C: a backslash immediately before the EOL
+// splices the next line onto the line.
+func (l *lexer) splicedLineEnd(pos Pos) Pos {
+	for {
+		end := l.lineEnd(pos)
+		if end == pos || int(end) == len(l.input) || l.input[end-1] != '\\' {
+			return end
+		}
+		pos = end + 1
+		if l.input[end] == cr {
+			pos++
+		}
+	}
+}
+
+// skipIncludeHeader consumes the rest of an #include, or #import, directive's
+// <header> name, if the directive is one, so that it isn't scanned for
+// comments. The current position must be just after the #.
+func (l *lexer) skipIncludeHeader() {
+	i := l.pos
+	for int(i) < len(l.input) && (l.input[i] == ' ' || l.input[i] == '\t') {
+		i++
+	}
+	j := i
+	for int(j) < len(l.input) && isIdentByte(l.input[j]) {
+		j++
+	}
+	switch string(l.input[i:j]) {
+	case "include", "include_next", "import":
+	default:
+		return
+	}
+	for int(j) < len(l.input) && (l.input[j] == ' ' || l.input[j] == '\t') {
+		j++
+	}
+	if int(j) >= len(l.input) || l.input[j] != '<' {
+		return
+	}
+	end := l.lineEnd(j)
+	k := bytes.IndexByte(l.input[j:end], '>')
+	if k < 0 {
+		return
+	}
+	l.pos = j + Pos(k) + 1
+}
+
+// skipNumber consumes a preprocessing number. A ' within it is a digit
+// separator, not the start of a character literal.
+func (l *lexer) skipNumber() {
+	for int(l.pos) < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case (c == '+' || c == '-') && bytes.IndexByte([]byte("eEpP"), l.input[l.pos-1]) >= 0:
+		case c == '.' || c == '\'' || isIdentByte(c):
+		default:
+			return
+		}
+		l.pos++
+	}
+}
+
+// isRawStringPrefix returns whether b is the encoding prefix of a raw
+// string.
+func isRawStringPrefix(b []byte) bool {
+	switch string(b) {
+	case "R", "LR", "uR", "UR", "u8R":
+		return true
+	}
+	return false
+}
+
+// rawStringMaxDelim is the maximum length of a raw string's delimiter.
+const rawStringMaxDelim = 16
+
+// rawStringDelim returns the length of the delimiter of the raw string whose
+// opening quote is at q. If the quote isn't followed by a valid delimiter and
+// a (, -1 is returned. A delimiter has at most 16 characters, none of which
+// may be a space, a parenthesis, a backslash, or a control character.
+func (l *lexer) rawStringDelim(q Pos) int {
+	for n := 0; n <= rawStringMaxDelim && int(q)+1+n < len(l.input); n++ {
+		switch c := l.input[int(q)+1+n]; {
+		case c == '(':
+			return n
+		case c == ' ' || c == ')' || c == '\\' || c < ' ' || c == 0x7f:
+			return -1
+		}
+	}
+	return -1
+}
+
+// lexRawString consumes a C++ raw string, R"delim(...)delim", including its
+// encoding prefix, and emits it as quoted text. The delimiter must be valid,
+// see rawStringDelim. If the raw string isn't terminated, false is returned.
+func (l *lexer) lexRawString() bool {
+	l.emitText()
+	q := int(l.pos) + bytes.IndexByte(l.input[l.pos:], '"')
+	n := l.rawStringDelim(Pos(q))
+	end := append(append([]byte(")"), l.input[q+1:q+1+n]...), '"')
+	j := bytes.Index(l.input[q+n+2:], end)
+	if j < 0 {
+		return false
+	}
+	l.pos = Pos(q + n + 2 + j + len(end))
+	l.emit(tokenQuotedText)
+	return true
+}
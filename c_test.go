@@ -0,0 +1,57 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestC(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "int a; // a\n/* b */int b;", "int a; \nint b;", ""},
+		{"preprocessor", "#include <stdio.h>\n#define A 1 // a\n  # if A\n#endif\n", "#include <stdio.h>\n#define A 1 \n  # if A\n#endif\n", ""},
+		{"includeHeader", "#include <a//b.h> // a\n#include \"c//d.h\"\n", "#include <a//b.h> \n#include \"c//d.h\"\n", ""},
+		{"splice", "int a; // a \\\n still a\nint b;\n", "int a; \nint b;\n", ""},
+		{"spliceCRLF", "int a; // a \\\r\n still a\r\nint b;\r\n", "int a; \r\nint b;\r\n", ""},
+		{"spliceEOF", "int a; // a \\", "int a; ", ""},
+		{"char", "c = '\"'; // a\nd = '\\''; /* b */", "c = '\"'; \nd = '\\''; ", ""},
+		{"string", "s = \"/* a */ \\\" // b\"; // c\n", "s = \"/* a */ \\\" // b\"; \n", ""},
+		{"wide", "c = L'\"'; s = u8\"//\"; // a\n", "c = L'\"'; s = u8\"//\"; \n", ""},
+		{"digitSeparator", "n = 1'000'000; // a\n", "n = 1'000'000; \n", ""},
+		{"exponent", "f = 1e-5/2; // a\n", "f = 1e-5/2; \n", ""},
+		{"notRaw", "R\"(a)\" // b\n", "R\"(a)\" \n", ""},
+		{"unclosedChar", "#error don't // a\nint b; // c\n", "#error don't \nint b; \n", ""},
+		{"unclosedCharIf0", "#if 0\nit's /* a */\n#endif // b\n", "#if 0\nit's \n#endif \n", ""},
+		{"spliceString", "s = \"a \\\n// b\"; // c\n", "s = \"a \\\n// b\"; \n", ""},
+		{"unclosedComment", "int a; /* b", "", "index 7: unclosed block comment"},
+		{"unclosedString", "s = \"a", "", "index 4: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: C}, tests)
+}
+
+func TestCPP(t *testing.T) {
+	tests := []profileTest{
+		{"raw", "s = R\"(a \" // b)\"; // c\n", "s = R\"(a \" // b)\"; \n", ""},
+		{"rawDelim", "s = R\"x(a )\" /* b */)x\"; /* c */", "s = R\"x(a )\" /* b */)x\"; ", ""},
+		{"rawPrefix", "s = u8R\"--(a\n// b\n)--\"; // c\n", "s = u8R\"--(a\n// b\n)--\"; \n", ""},
+		{"notRaw", "FOOR\"a\" // b\n", "FOOR\"a\" \n", ""},
+		{"digitSeparator", "n = 0x1'ff; // a\n", "n = 0x1'ff; \n", ""},
+		{"rawDelimSpace", "s = R\"a b(\" // c\n", "s = R\"a b(\" \n", ""},
+		{"rawDelimLong", "s = R\"12345678901234567(\" // c\n", "s = R\"12345678901234567(\" \n", ""},
+		{"rawDelimMax", "s = R\"1234567890123456(//)1234567890123456\" // c\n", "s = R\"1234567890123456(//)1234567890123456\" \n", ""},
+		{"unclosedRaw", "s = R\"x(a)\"", "", "index 4: unterminated raw string"},
+	}
+	testProfile(t, Stripper{Profile: CPP}, tests)
+}
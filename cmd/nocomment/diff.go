@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/mohae/nocomment"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// runDiff runs the diff command: it compares the code of two files, ignoring
+// their comments, and prints a unified diff of the differences. Like diff,
+// it returns 0 if the code is the same, 1 if it differs, and 2 on error.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet(app+" diff", flag.ContinueOnError)
+	var profile string
+	fs.StringVar(&profile, "profile", "", "name of the files' profile: detected from the first file if not set")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s diff [-profile name] a b\n", app)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	nameA, nameB := fs.Arg(0), fs.Arg(1)
+	a, err := ioutil.ReadFile(nameA)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	b, err := ioutil.ReadFile(nameB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var p *nocomment.Profile
+	if profile != "" {
+		p = nocomment.LookupProfile(profile)
+		if p == nil {
+			fmt.Fprintf(os.Stderr, "%s: unknown profile: %s\n", app, profile)
+			return 2
+		}
+	} else if p = nocomment.DetectProfile(nameA, a); p == nil {
+		p = nocomment.DetectProfile(nameB, b)
+	}
+	codeA, err := nocomment.CodeOnly(a, p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s: %s\n", app, nameA, err)
+		return 2
+	}
+	codeB, err := nocomment.CodeOnly(b, p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s: %s\n", app, nameB, err)
+		return 2
+	}
+	if bytes.Equal(codeA, codeB) {
+		return 0
+	}
+	writeUnified(os.Stdout, nameA, nameB, splitLines(codeA), splitLines(codeB))
+	return 1
+}
+
+// noEOL marks a last line that has no EOL, which can't be part of a line,
+// so that it differs from the same line with one.
+const noEOL = "\n"
+
+// splitLines splits b into lines without their EOLs. A last line without an
+// EOL ends with noEOL.
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	s := strings.TrimSuffix(string(b), "\n")
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	if len(s) == len(b) {
+		lines[len(lines)-1] += noEOL
+	}
+	return lines
+}
+
+// edit is a line of an edit script: op is ' ' for a line in both a and b,
+// '-' for a line only in a, and '+' for a line only in b.
+type edit struct {
+	op   byte
+	line string
+}
+
+// diffLines returns the shortest edit script that turns a into b, using
+// Myers' algorithm. The common prefix and suffix are left out of the search,
+// and only the part of each step's state that backtracking needs is kept, so
+// memory is proportional to the square of the number of differences.
+func diffLines(a, b []string) []edit {
+	var edits []edit
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		edits = append(edits, edit{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	same := 0
+	for same < len(a) && same < len(b) && a[len(a)-1-same] == b[len(b)-1-same] {
+		same++
+	}
+	suffix := a[len(a)-same:]
+	a, b = a[:len(a)-same], b[:len(b)-same]
+	n, m := len(a), len(b)
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+	// trace holds, for each step d, v[k] for k from -d-1 to d+1 as it was
+	// before the step: all that backtracking from the step looks at
+	var trace [][]int
+	d := 0
+search:
+	for ; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+	var script []edit
+	x, y := n, m
+	for ; d >= 0; d-- {
+		// w[k+d+1] is v[k] before step d
+		w := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && w[k+d] < w[k+d+2]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := w[prevK+d+1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			script = append(script, edit{' ', a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				script = append(script, edit{'+', b[prevY]})
+			} else {
+				script = append(script, edit{'-', a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i := len(script) - 1; i >= 0; i-- {
+		edits = append(edits, script[i])
+	}
+	for _, l := range suffix {
+		edits = append(edits, edit{' ', l})
+	}
+	return edits
+}
+
+// writeUnified writes a unified diff of a and b to w.
+func writeUnified(w io.Writer, nameA, nameB string, a, b []string) {
+	edits := diffLines(a, b)
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", nameA, nameB)
+	// lineA and lineB are the line numbers, in a and b, of edits[i]
+	lineA, lineB := 1, 1
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			lineA++
+			lineB++
+			i++
+			continue
+		}
+		// a hunk starts with the context before the change and ends once
+		// there are more unchanged lines than the context on both sides
+		start := i
+		for start > 0 && i-start < diffContext && edits[start-1].op == ' ' {
+			start--
+		}
+		startA, startB := lineA-(i-start), lineB-(i-start)
+		end := i
+		for same := 0; end < len(edits) && same <= 2*diffContext; end++ {
+			if edits[end].op == ' ' {
+				same++
+			} else {
+				same = 0
+			}
+		}
+		// trim the context after the last change
+		for end > i && edits[end-1].op == ' ' {
+			end--
+		}
+		last := end
+		for end < len(edits) && end-last < diffContext && edits[end].op == ' ' {
+			end++
+		}
+		var countA, countB int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				countA++
+			}
+			if e.op != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(startA, countA), hunkRange(startB, countB))
+		for _, e := range edits[start:end] {
+			if strings.HasSuffix(e.line, noEOL) {
+				fmt.Fprintf(w, "%c%s\n\\ No newline at end of file\n", e.op, strings.TrimSuffix(e.line, noEOL))
+				continue
+			}
+			fmt.Fprintf(w, "%c%s\n", e.op, e.line)
+		}
+		for _, e := range edits[i:end] {
+			if e.op != '+' {
+				lineA++
+			}
+			if e.op != '-' {
+				lineB++
+			}
+		}
+		i = end
+	}
+}
+
+// hunkRange formats the start and length of a hunk's range. An empty range
+// starts at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
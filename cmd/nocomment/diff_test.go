@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		diff string
+	}{
+		{"added", "a\nb\n", "a\nc\nb\n", "@@ -1,2 +1,3 @@\n a\n+c\n b\n"},
+		{"removed", "a\nb\nc\n", "a\nc\n", "@@ -1,3 +1,2 @@\n a\n-b\n c\n"},
+		{"changed", "a\n", "b\n", "@@ -1 +1 @@\n-a\n+b\n"},
+		{"fromEmpty", "", "a\n", "@@ -0,0 +1 @@\n+a\n"},
+		{"context", "1\n2\n3\n4\n5\n6\n7\n8\n", "1\n2\n3\n4\nx\n6\n7\n8\n", "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n"},
+		{"hunks", "a\n1\n2\n3\n4\n5\n6\n7\nb\n", "x\n1\n2\n3\n4\n5\n6\n7\ny\n", "@@ -1,4 +1,4 @@\n-a\n+x\n 1\n 2\n 3\n@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+y\n"},
+		{"same", "a\nb\n", "a\nb\n", ""},
+		{"middle", "a\nb\nc\nd\ne\n", "a\nx\nc\ny\ne\n", "@@ -1,5 +1,5 @@\n a\n-b\n+x\n c\n-d\n+y\n e\n"},
+		{"noEOL", "a\nb\n", "a\nb", "@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n"},
+		{"onlyNoEOL", "a", "a\n", "@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n"},
+		{"joined", "a\n1\n2\n3\n4\n5\n6\nb\n", "x\n1\n2\n3\n4\n5\n6\ny\n", "@@ -1,8 +1,8 @@\n-a\n+x\n 1\n 2\n 3\n 4\n 5\n 6\n-b\n+y\n"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		writeUnified(&buf, "a", "b", splitLines([]byte(test.a)), splitLines([]byte(test.b)))
+		want := "--- a\n+++ b\n" + test.diff
+		if buf.String() != want {
+			t.Errorf("%s: got %q want %q", test.name, buf.String(), want)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"", "", 0},
+		{"a\nb\nc\n", "", 3},
+		{"a\nb\nc\na\nb\nb\na\n", "c\nb\na\nb\na\nc\n", 5},
+		{"x\na\nb\ny\n", "x\nb\na\ny\n", 2},
+	}
+	for _, test := range tests {
+		a, b := splitLines([]byte(test.a)), splitLines([]byte(test.b))
+		// the script must be the shortest that turns a into b
+		var gotA, gotB []string
+		edits := 0
+		for _, e := range diffLines(a, b) {
+			if e.op != ' ' {
+				edits++
+			}
+			if e.op != '+' {
+				gotA = append(gotA, e.line)
+			}
+			if e.op != '-' {
+				gotB = append(gotB, e.line)
+			}
+		}
+		if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+			t.Errorf("%q %q: got %q %q", test.a, test.b, gotA, gotB)
+		}
+		if edits != test.edits {
+			t.Errorf("%q %q: got %d edits want %d", test.a, test.b, edits, test.edits)
+		}
+	}
+}
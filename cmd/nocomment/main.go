@@ -10,51 +10,124 @@ import (
 	"github.com/mohae/nocomment"
 )
 
+// minConfidence is the least confidence a guessed profile must have to be
+// used; otherwise the default rules are used.
+const minConfidence = 0.5
+
 var (
-	app     = filepath.Base(os.Args[0])
-	in, out string
+	app         = filepath.Base(os.Args[0])
+	in, out     string
+	profileFile string
+	profile     string
+	trim        bool
+	dropEmpty   bool
+	maxBlank    int
+	sidecar     string
+	restore     string
 )
 
 func init() {
-	flag.StringVar(&in, "input", "", "input file: required")
-	flag.StringVar(&in, "i", "", "input file: required (short)")
-	flag.StringVar(&out, "output", "", "output file: required")
-	flag.StringVar(&out, "o", "", "output file: required (short)")
+	flag.StringVar(&in, "input", "-", "input file: - for stdin")
+	flag.StringVar(&in, "i", "-", "input file: - for stdin (short)")
+	flag.StringVar(&out, "output", "-", "output file: - for stdout")
+	flag.StringVar(&out, "o", "-", "output file: - for stdout (short)")
+	flag.StringVar(&profileFile, "profile-file", "", "profile definition file: the input's comment rules")
+	flag.StringVar(&profile, "profile", "", "name of the input's profile: detected from the input if not set")
+	flag.BoolVar(&trim, "trim", false, "trim trailing whitespace from lines that comments were removed from")
+	flag.BoolVar(&dropEmpty, "drop-empty", false, "drop lines that are empty once their comments are removed")
+	flag.IntVar(&maxBlank, "max-blank", 0, "collapse runs of blank lines to at most this many: 0 for no limit")
+	flag.StringVar(&sidecar, "sidecar", "", "file to save the removed comments to, so that they can be restored")
+	flag.StringVar(&restore, "restore", "", "sidecar file whose comments are restored to the input, instead of removing comments")
 }
 
 func main() {
-	flag.Parse()
-
-	if in == "" {
-		fmt.Fprintf(os.Stderr, "%s: \"input\" is a required flag\n", app)
-		flag.Usage()
-		os.Exit(1)
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:]))
 	}
+	flag.Parse()
 
-	// If the output wasn't specified, save
-	if out == "" {
-		fmt.Fprintf(os.Stderr, "\"output\" is a required flag\n")
-		flag.Usage()
-		os.Exit(1)
-	}
 	// read the input
-	b, err := ioutil.ReadFile(in)
+	var b []byte
+	var err error
+	if in == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(in)
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	var s nocomment.Stripper
-	b, err = s.Clean(b)
+	s := nocomment.Stripper{TrimTrailingSpace: trim, DropEmptyLines: dropEmpty, MaxBlankLines: maxBlank}
+	if profileFile != "" {
+		s.Profile, err = nocomment.LoadProfile(profileFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: error loading profile: %s\n", app, err)
+			os.Exit(1)
+		}
+	}
+	if profile != "" {
+		s.Profile = nocomment.LookupProfile(profile)
+		if s.Profile == nil {
+			fmt.Fprintf(os.Stderr, "%s: unknown profile: %s\n", app, profile)
+			os.Exit(1)
+		}
+	}
+	switch {
+	case restore != "":
+		var sc []byte
+		sc, err = ioutil.ReadFile(restore)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		b, err = nocomment.Restore(b, sc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: error restoring comments: %s\n", app, err)
+			os.Exit(1)
+		}
+	case sidecar != "":
+		if s.Profile == nil {
+			s.Profile = detect(b)
+		}
+		var sc []byte
+		b, sc, err = s.StripReversible(b)
+		if err == nil {
+			err = ioutil.WriteFile(sidecar, sc, 0644)
+		}
+	case in == "-" && s.Profile == nil:
+		s.Profile = detect(b)
+		b, err = s.Clean(b)
+	default:
+		b, err = s.CleanFile(in, b)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s: error removing comments: %s\n", app, err)
 		os.Exit(1)
 	}
 
-	err = ioutil.WriteFile(out, b, 0644)
+	if out == "-" {
+		_, err = os.Stdout.Write(b)
+	} else {
+		err = ioutil.WriteFile(out, b, 0644)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s: error writing file: %s\n", app, err)
 		os.Exit(1)
 	}
 	os.Exit(0)
 }
+
+// detect returns the profile of the input, b: it is detected from the input
+// file's name or, for stdin, guessed from its content. If the guess isn't
+// confident, nil is returned.
+func detect(b []byte) *nocomment.Profile {
+	if in != "-" {
+		return nocomment.DetectProfile(in, b)
+	}
+	if p, confidence := nocomment.GuessProfile(b); confidence >= minConfidence {
+		return p
+	}
+	return nil
+}
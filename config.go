@@ -0,0 +1,121 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// INI is the profile for INI files: ; and # comments. Comments must be the
+// first thing on their line, other than whitespace; a ; or # elsewhere is
+// part of the value.
+var INI = &Profile{
+	Name:       "ini",
+	Aliases:    []string{"dosini"},
+	Extensions: []string{".ini", ".cfg"},
+	Hints:      []string{"\n[", "]\n"},
+	lexText:    lexINI,
+}
+
+// Properties is the profile for Java .properties files: # and ! comments.
+// Comments must be the first thing on their line, other than whitespace. A
+// line that ends with an unescaped backslash continues on the next line; a
+// continuation line is never a comment.
+var Properties = &Profile{
+	Name:       "properties",
+	Aliases:    []string{"jproperties"},
+	Extensions: []string{".properties"},
+	lexText:    lexProperties,
+}
+
+// Env is the profile for .env files: # comments. An unquoted # starts a
+// comment when it is the first thing on its line, other than whitespace, or
+// when it follows whitespace. Values may be quoted with " or ', and may be
+// preceded by whitespace; a # within quotes is part of the value. A quote
+// that doesn't start a value is just a character.
+var Env = &Profile{
+	Name:       "env",
+	Aliases:    []string{"dotenv"},
+	Extensions: []string{".env"},
+	Filenames:  []string{".env"},
+	lexText:    lexEnv,
+}
+
+// lexINI lexes INI files.
+func lexINI(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == ';' && l.atFirstNonSpace():
+			l.lexLineComment(tokenLineComment)
+		case c == '#' && l.atFirstNonSpace():
+			l.lexLineComment(tokenShellComment)
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// lexProperties lexes .properties files.
+func lexProperties(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\f':
+			l.pos++
+		case c == nl || c == cr:
+			l.pos++
+		case c == '#':
+			l.lexLineComment(tokenShellComment)
+		case c == '!':
+			l.lexLineComment(tokenLineComment)
+		default:
+			// a key value pair, which may continue over multiple lines
+			for {
+				l.pos = l.lineEnd(l.pos)
+				if int(l.pos) == len(l.input) || !l.escaped(`\`) {
+					break
+				}
+				l.skipEOL()
+			}
+		}
+	}
+	return lexEOF
+}
+
+// lexEnv lexes .env files.
+func lexEnv(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '#' && (l.pos == 0 || isSpace(l.input[l.pos-1])):
+			l.lexLineComment(tokenShellComment)
+		case c == '"' && l.atEnvValue():
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '\'' && l.atEnvValue():
+			if !l.lexQuoted(c, 0, false) {
+				return l.errorf("unterminated quoted string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atEnvValue returns whether the current position is the start of a value:
+// it follows the =, possibly after spaces or tabs.
+func (l *lexer) atEnvValue() bool {
+	i := l.pos
+	for i > 0 && (l.input[i-1] == ' ' || l.input[i-1] == '\t') {
+		i--
+	}
+	return i > 0 && l.input[i-1] == '='
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestINI(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "; a\n# b\n[section]\n  ; c\nkey=value\n", "\n\n[section]\n  \nkey=value\n", ""},
+		{"delimiterInValue", "url=http://a/#anchor\npath=a;b # c\n", "url=http://a/#anchor\npath=a;b # c\n", ""},
+		{"crlf", "; a\r\nkey=value\r\n", "\r\nkey=value\r\n", ""},
+	}
+	testProfile(t, Stripper{Profile: INI}, tests)
+}
+
+func TestProperties(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "# a\n! b\n  # c\nkey=value\n", "\n\n  \nkey=value\n", ""},
+		{"delimiterInValue", "color=#ff0000\nmsg=Hi! # there\n", "color=#ff0000\nmsg=Hi! # there\n", ""},
+		{"continuation", "list=a,\\\n    # b,\\\n    ! c\n# d\n", "list=a,\\\n    # b,\\\n    ! c\n\n", ""},
+		{"continuationCRLF", "list=a,\\\r\n  #b\r\n#c\r\n", "list=a,\\\r\n  #b\r\n\r\n", ""},
+		{"escapedBackslash", "path=c:\\\\\n# a\n", "path=c:\\\\\n\n", ""},
+		{"commentBackslash", "# a \\\nkey=value\n", "\nkey=value\n", ""},
+		{"continuationEOF", "key=a\\", "key=a\\", ""},
+	}
+	testProfile(t, Stripper{Profile: Properties}, tests)
+}
+
+func TestEnv(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "# a\nKEY=value # b\n  # c\n", "\nKEY=value \n  \n", ""},
+		{"delimiterInValue", "COLOR=#fff\nURL=http://a/#b\n", "COLOR=#fff\nURL=http://a/#b\n", ""},
+		{"quoted", "A=\"a # b\" # c\nB='a # \\' # d\n", "A=\"a # b\" \nB='a # \\' \n", ""},
+		{"quotedEscape", "A=\"a \\\" # b\" # c\n", "A=\"a \\\" # b\" \n", ""},
+		{"quotedAfterSpace", "A= \"a # b\" # c\nB =\t'a # b'\n", "A= \"a # b\" \nB =\t'a # b'\n", ""},
+		{"multiline", "KEY=\"-----BEGIN\n# not a comment\n-----END\"\n", "KEY=\"-----BEGIN\n# not a comment\n-----END\"\n", ""},
+		{"apostrophe", "MSG=it's # a\n", "MSG=it's \n", ""},
+		{"unclosedQuote", "KEY=\"a", "", "index 4: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Env}, tests)
+}
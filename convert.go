@@ -0,0 +1,338 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// blockPairs are the delimiters of block comments that aren't C style.
+var blockPairs = [][2]string{
+	{"<!--", "-->"},
+	{"(*", "*)"},
+	{"{-", "-}"},
+	{"--[[", "]]"},
+	{"#|", "|#"},
+	{"<#", "#>"},
+}
+
+// Converter converts the comments in its input into one style, e.g. # and
+// // comments into // comments, or runs of // comments into /* */ comments.
+//
+// Only comments whose delimiters are known are converted: C++, shell, and C
+// style comments, comments that use the delimiters of a profile file, and
+// other line comments that start with punctuation, e.g. SQL's --. Directive,
+// legal, and doc comments are left alone, as their delimiters matter, as are
+// line comments that continue on the next line.
+type Converter struct {
+	// Profile: the comment and quoting rules of the input's language. If nil,
+	// the default rules are used.
+	Profile *Profile
+	// Line is the line comment delimiter to convert to, e.g. //.
+	Line string
+	// Begin and End are the block comment delimiters to convert to, e.g. /*
+	// and */.
+	Begin, End string
+	// Blocks: convert comments into block comments: a run of line comments,
+	// each alone on its line with the same indentation, becomes one block
+	// comment. Otherwise, comments are converted into line comments; a block
+	// comment becomes a run of line comments, unless there is code after it
+	// on its last line, in which case it is left alone.
+	Blocks bool
+}
+
+// Convert returns the input with its comments converted. Text in a line
+// comment that would end the block comment it's converted into, e.g. */, is
+// escaped by inserting a space, e.g. * /; so is the begin delimiter, if the
+// block comments nest. The lines of a converted comment are indented like its
+// first line, or, if there is code before it, line up with it; decorations on
+// the lines of a block comment, e.g. the * of /** * */, are removed. A
+// converted comment must be a comment by the rules of the input; otherwise an
+// error is returned.
+func (c *Converter) Convert(input []byte) ([]byte, error) {
+	if c.Blocks && (c.Begin == "" || c.End == "") {
+		return nil, errors.New("block comment delimiters are required")
+	}
+	if !c.Blocks && c.Line == "" {
+		return nil, errors.New("a line comment delimiter is required")
+	}
+	l := lexProfile(input, c.Profile)
+	tokens, err := l.collect()
+	if err != nil {
+		return nil, err
+	}
+	s := Stripper{Profile: c.Profile}
+	nested := c.Blocks && c.nests()
+	b := make([]byte, 0, len(input))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		begin, end, ok := c.delimiters(t)
+		if !ok {
+			b = append(b, t.value...)
+			continue
+		}
+		indent, alone := lineIndent(input, int(t.pos))
+		var r string
+		n := 1
+		switch {
+		case end == "" && c.Blocks:
+			r, n = c.lineRunToBlock(tokens[i:], begin, indent, alone, nested)
+		case end == "":
+			r = c.Line + t.value[len(begin):]
+		case c.Blocks:
+			r = c.Begin + c.escape(t.value[len(begin):len(t.value)-len(end)], nested) + c.End
+		case alone:
+			r = c.blockToLines(t.value[len(begin):len(t.value)-len(end)], indent)
+		default:
+			// the lines after the first line up with the comment
+			r = c.blockToLines(t.value[len(begin):len(t.value)-len(end)], columnIndent(input, int(t.pos)))
+		}
+		last := tokens[i+n-1]
+		next := int(last.pos) + len(last.value)
+		followed := next < len(input) && input[next] != nl && input[next] != cr
+		if end != "" && !c.Blocks && followed {
+			b = append(b, t.value...)
+			continue
+		}
+		if !s.validReplacement(input, l.embedding(t.pos), int(t.pos), next, []byte(r)) {
+			return nil, token{tokenError, t.pos, fmt.Sprintf("converted %s is not a comment: %q", commentType(t.typ), r)}
+		}
+		b = append(b, r...)
+		i += n - 1
+	}
+	return b, nil
+}
+
+// delimiters returns the delimiters of the comment token, t, if it can be
+// converted; end is empty for line comments.
+func (c *Converter) delimiters(t token) (begin, end string, ok bool) {
+	switch t.typ {
+	case tokenCPPComment:
+		begin = "//"
+	case tokenShellComment:
+		begin = "#"
+	case tokenCComment:
+		begin, end = "/*", "*/"
+	case tokenLineComment:
+		if d := c.profileDelimiter(t.value); d != nil && d.kind == lineDelim {
+			begin = d.begin
+			break
+		}
+		i := 0
+		for i < len(t.value) && !isIdentByte(t.value[i]) && !isSpace(t.value[i]) {
+			i++
+		}
+		begin = t.value[:i]
+	case tokenBlockComment:
+		if d := c.profileDelimiter(t.value); d != nil && d.kind == blockDelim && !d.nested {
+			begin, end = d.begin, d.end
+			break
+		}
+		for _, pair := range blockPairs {
+			if strings.HasPrefix(t.value, pair[0]) {
+				begin, end = pair[0], pair[1]
+				break
+			}
+		}
+	}
+	if begin == "" || !strings.HasPrefix(t.value, begin) {
+		return "", "", false
+	}
+	if end == "" {
+		// a line comment that continues on the next line can't be converted
+		text := strings.TrimSuffix(t.value, trailingEOL(t.value))
+		return begin, "", strings.IndexByte(text, nl) < 0
+	}
+	return begin, end, len(t.value) >= len(begin)+len(end) && strings.HasSuffix(t.value, end)
+}
+
+// profileDelimiter returns the delimiter of the Converter's profile that the
+// comment, s, starts with, if the profile's delimiters are known.
+func (c *Converter) profileDelimiter(s string) *delimiter {
+	if c.Profile == nil || c.Profile.delims == nil {
+		return nil
+	}
+	return c.Profile.delims.match([]byte(s))
+}
+
+// lineRunToBlock converts the run of line comments that starts with the
+// first token into a block comment and returns it and the number of tokens
+// it replaces. The comments must use the same delimiter and, if the first
+// one is alone on its line, be alone on consecutive lines with the same
+// indentation. If block comments nest, their begin delimiter is escaped too.
+func (c *Converter) lineRunToBlock(tokens []token, begin, indent string, alone, nested bool) (string, int) {
+	n := 1
+	bodies := []string{tokens[0].value[len(begin):]}
+	var eols []string
+	for alone {
+		// the EOL is either part of the comment or of the text that follows
+		gap := trailingEOL(tokens[n-1].value)
+		k := n
+		if k < len(tokens) && tokens[k].typ == tokenText {
+			gap += tokens[k].value
+			k++
+		}
+		if k >= len(tokens) || tokens[k].typ != tokens[0].typ || !strings.HasPrefix(tokens[k].value, begin) {
+			break
+		}
+		eol := strings.TrimSuffix(gap, indent)
+		if (eol != "\n" && eol != "\r\n") || len(eol)+len(indent) != len(gap) {
+			break
+		}
+		if next := tokens[k].value; strings.IndexByte(strings.TrimSuffix(next, trailingEOL(next)), nl) >= 0 {
+			break
+		}
+		eols = append(eols, eol)
+		bodies = append(bodies, tokens[k].value[len(begin):])
+		n = k + 1
+	}
+	eol := trailingEOL(tokens[n-1].value)
+	pad := strings.Repeat(" ", len(c.Begin))
+	r := c.Begin
+	for i, body := range bodies {
+		body = strings.TrimRight(strings.TrimSuffix(body, trailingEOL(body)), " \t")
+		body = c.escape(body, nested)
+		switch {
+		case i == 0:
+			r += body
+		case body == "":
+			r += eols[i-1]
+		default:
+			r += eols[i-1] + indent + pad + body
+		}
+	}
+	return r + " " + c.End + eol, n
+}
+
+// blockToLines converts the body of a block comment into a run of line
+// comments indented by indent.
+func (c *Converter) blockToLines(body, indent string) string {
+	eol := "\n"
+	if strings.Contains(body, "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.Split(strings.Replace(body, "\r\n", "\n", -1), "\n")
+	// the lines after the first share indentation and may be decorated
+	rest := lines[1:]
+	common := -1
+	for _, line := range rest {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); common < 0 || n < common {
+			common = n
+		}
+	}
+	decorated := common >= 0
+	for i, line := range rest {
+		if strings.TrimSpace(line) == "" {
+			rest[i] = ""
+			continue
+		}
+		rest[i] = line[common:]
+		decorated = decorated && strings.HasPrefix(rest[i], "*")
+	}
+	if decorated {
+		// e.g. /** on the first line
+		lines[0] = strings.TrimLeft(lines[0], "*")
+		for i := range rest {
+			rest[i] = strings.TrimPrefix(rest[i], "*")
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	var r string
+	for i, line := range lines {
+		if i > 0 {
+			r += eol + indent
+		}
+		r += c.Line
+		if line != "" && line[0] != ' ' && line[0] != '\t' {
+			r += " "
+		}
+		r += line
+	}
+	return r
+}
+
+// escape returns the body of a block comment, s, with the text that would
+// end the comment escaped: the end delimiter and, if block comments nest,
+// the begin delimiter.
+func (c *Converter) escape(s string, nested bool) string {
+	s = escapeDelimiter(s, c.End)
+	if nested {
+		s = escapeDelimiter(s, c.Begin)
+	}
+	return s
+}
+
+// nests returns whether the block comments being converted to nest by the
+// rules of the Converter's profile, e.g. Rust's /* */ comments.
+func (c *Converter) nests() bool {
+	tokens, err := lexTokens([]byte(c.Begin+" "+c.Begin+" "+c.End+" "+c.End), c.Profile)
+	return err == nil && len(tokens) == 1 && commentType(tokens[0].typ) != none
+}
+
+// escapeDelimiter returns s with any occurrence of the block comment
+// delimiter, d, broken up by a space.
+func escapeDelimiter(s, d string) string {
+	if len(d) < 2 {
+		return s
+	}
+	return strings.Replace(s, d, d[:1]+" "+d[1:], -1)
+}
+
+// lineIndent returns the whitespace at the start of the line that pos is on
+// and whether only whitespace precedes pos on that line.
+func lineIndent(input []byte, pos int) (string, bool) {
+	start := pos
+	for start > 0 && input[start-1] != nl {
+		start--
+	}
+	i := start
+	for i < pos && (input[i] == ' ' || input[i] == '\t') {
+		i++
+	}
+	return string(input[start:i]), i == pos
+}
+
+// columnIndent returns whitespace as wide as the text before pos on its
+// line: its tabs, with everything else replaced by spaces.
+func columnIndent(input []byte, pos int) string {
+	start := bytes.LastIndexByte(input[:pos], nl) + 1
+	var b strings.Builder
+	for _, r := range string(input[start:pos]) {
+		if r == '\t' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// lexTokens returns the tokens of the input, lexed using the rules of p.
+func lexTokens(input []byte, p *Profile) ([]token, error) {
+	return lexProfile(input, p).collect()
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+type convertTest struct {
+	name   string
+	input  string
+	output string
+	err    string
+}
+
+func testConvert(t *testing.T, c Converter, tests []convertTest) {
+	for _, test := range tests {
+		result, err := c.Convert([]byte(test.input))
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; wanted %q", test.name, test.err)
+			continue
+		}
+		if string(result) != test.output {
+			t.Errorf("%s: got %q want %q", test.name, string(result), test.output)
+		}
+	}
+}
+
+func TestConvertLines(t *testing.T) {
+	tests := []convertTest{
+		{"empty", "", "", ""},
+		{"hash", "a = 1 # b\n# c\n", "a = 1 // b\n// c\n", ""},
+		{"block", "\t/* a\n\t   b */\n", "\t// a\n\t// b\n", ""},
+		{"decorated", "  /**\n   * a\n   *   b\n   */\nc\n", "  // a\n  //   b\nc\n", ""},
+		{"decoratedEnd", "/*\n * a\n */\nc\n", "// a\nc\n", ""},
+		{"crlf", "/* a\r\n   b */\r\n", "// a\r\n// b\r\n", ""},
+		{"emptyBlock", "a /**/\n", "a //\n", ""},
+		{"afterCode", "a = 1 /* b\n        c */\n\td = 2 /* e\n\t        f */\n", "a = 1 // b\n      // c\n\td = 2 // e\n\t      // f\n", ""},
+		{"followed", "a /* b */ c\n", "a /* b */ c\n", ""},
+		{"quoted", "a = \"# b\"\n", "a = \"# b\"\n", ""},
+		{"heredoc", "<<EOT\n# a\nEOT\n", "<<EOT\n# a\nEOT\n", ""},
+	}
+	testConvert(t, Converter{Profile: HCL, Line: "//"}, tests)
+
+	sql := []convertTest{
+		{"block", "SELECT a /* b */\n/* c\n   d */\n", "SELECT a -- b\n-- c\n-- d\n", ""},
+	}
+	testConvert(t, Converter{Profile: SQL, Line: "--"}, sql)
+
+	invalid := []convertTest{
+		{"notComment", "a # b\n", "", "index 2: converted shell comment is not a comment: \"// b\""},
+	}
+	testConvert(t, Converter{Profile: Shell, Line: "//"}, invalid)
+	testConvert(t, Converter{Profile: Shell}, []convertTest{{"noLine", "a\n", "", "a line comment delimiter is required"}})
+}
+
+func TestConvertBlocks(t *testing.T) {
+	tests := []convertTest{
+		{"empty", "", "", ""},
+		{"trailing", "a := 1 // b\n", "a := 1 /* b */\n", ""},
+		{"run", "\t// a\n\t//\n\t// b */ c\nd()\n", "\t/* a\n\n\t   b * / c */\nd()\n", ""},
+		{"indentChanges", "// a\n\t// b\n", "/* a */\n\t/* b */\n", ""},
+		{"blankLine", "// a\n\n// b\n", "/* a */\n\n/* b */\n", ""},
+		{"trailingRun", "a // b\n// c\n", "a /* b */\n/* c */\n", ""},
+		{"block", "/* a */ b\n", "/* a */ b\n", ""},
+		{"directive", "//go:generate a\n// b\n", "//go:generate a\n/* b */\n", ""},
+		{"crlf", "// a\r\n// b\r\n", "/* a\r\n   b */\r\n", ""},
+	}
+	testConvert(t, Converter{Profile: Go, Begin: "/*", End: "*/", Blocks: true}, tests)
+
+	// with the default rules, line comments include their EOL
+	legacy := []convertTest{
+		{"run", "# a\n# b\nc\n", "/* a\n   b */\nc\n", ""},
+		{"mixed", "// a\n# b\n", "/* a */\n/* b */\n", ""},
+	}
+	testConvert(t, Converter{Begin: "/*", End: "*/", Blocks: true}, legacy)
+
+	rust := []convertTest{
+		{"nested", "// a /* b\n// c */ d\n", "/* a / * b\n   c * / d */\n", ""},
+	}
+	testConvert(t, Converter{Profile: Rust, Begin: "/*", End: "*/", Blocks: true}, rust)
+	// Go's block comments don't nest
+	testConvert(t, Converter{Profile: Go, Begin: "/*", End: "*/", Blocks: true}, []convertTest{
+		{"notNested", "// a /* b\n", "/* a /* b */\n", ""},
+	})
+
+	lua := []convertTest{
+		{"run", "-- a\n-- b\nc = 1 -- d\n", "--[[ a\n     b ]]\nc = 1 --[[ d ]]\n", ""},
+	}
+	testConvert(t, Converter{Profile: Lua, Begin: "--[[", End: "]]", Blocks: true}, lua)
+}
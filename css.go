@@ -0,0 +1,42 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// CSS is the profile for CSS: /* */ comments. Strings are delimited by " or '
+// and use backslash escapes.
+var CSS = &Profile{
+	Name:       "css",
+	Extensions: []string{".css"},
+	Hints:      []string{"color:", "margin:", "padding:", "px;", "font-", "@media", "background"},
+	lexText:    lexCSS,
+}
+
+// lexCSS lexes CSS.
+func lexCSS(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '/' && l.hasPrefix("/*"):
+			if !l.lexDelimited("/*", "*/", tokenCComment) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '"' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
@@ -0,0 +1,31 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestCSS(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "/* a */p { color: red; } /* b\n c */", "p { color: red; } ", ""},
+		{"string", "a::after { content: \"/* a */\"; } /* b */", "a::after { content: \"/* a */\"; } ", ""},
+		{"escape", "a::after { content: '\\'/*'; }", "a::after { content: '\\'/*'; }", ""},
+		{"url", "a { background: url(http://a/b.png); }", "a { background: url(http://a/b.png); }", ""},
+		{"unclosedComment", "p { } /* a", "", "index 6: unclosed block comment"},
+		{"unclosedString", "p { content: \"a }", "", "index 13: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: CSS}, tests)
+}
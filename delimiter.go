@@ -0,0 +1,157 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// delimKind is the kind of text a delimiter starts.
+type delimKind int
+
+const (
+	lineDelim delimKind = iota
+	blockDelim
+	quoteDelim
+)
+
+func (k delimKind) String() string {
+	switch k {
+	case lineDelim:
+		return "line comment"
+	case blockDelim:
+		return "block comment"
+	}
+	return "quote"
+}
+
+// delimiter starts, and for block comments and quotes ends, a comment or
+// quoted text.
+type delimiter struct {
+	kind      delimKind
+	begin     string
+	end       string    // the end delimiter of block comments and quotes
+	escape    string    // quotes: escapes the byte that follows it, or a doubled end if it's end; line comments: an escaped begin is text
+	nested    bool      // block comments: whether they nest
+	lineStart bool      // line comments: whether only whitespace may precede them on their line
+	typ       tokenType // the token type that is emitted
+}
+
+// delimiters is a set of delimiters. No delimiter's begin is a prefix of
+// another's, so at most one delimiter matches at any position.
+type delimiters []delimiter
+
+// defaultDelimiters are used when there is neither a profile nor any added
+// delimiters.
+var defaultDelimiters = delimiters{
+	{kind: lineDelim, begin: "//", typ: tokenCPPComment},
+	{kind: lineDelim, begin: "#", typ: tokenShellComment},
+	{kind: blockDelim, begin: "/*", end: "*/", typ: tokenCComment},
+	{kind: quoteDelim, begin: `"`, end: `"`, escape: `\`, typ: tokenQuotedText},
+}
+
+// add returns the set with d added. An error is returned if d is invalid or
+// if its begin conflicts with, or is ambiguous with, that of a delimiter in
+// the set: they are the same or one is a prefix of the other.
+func (ds delimiters) add(d delimiter) (delimiters, error) {
+	if d.begin == "" || (d.kind != lineDelim && d.end == "") {
+		return ds, fmt.Errorf("%s delimiter is empty", d.kind)
+	}
+	if strings.ContainsAny(d.begin, "\r\n") {
+		return ds, fmt.Errorf("%s %q contains an EOL", d.kind, d.begin)
+	}
+	if d.escape != "" && d.escape != d.end && strings.HasPrefix(d.end, d.escape) {
+		return ds, fmt.Errorf("escape %q is ambiguous with quote %q", d.escape, d.end)
+	}
+	for _, v := range ds {
+		switch {
+		case v.begin == d.begin:
+			return ds, fmt.Errorf("%s %q conflicts with %s %q", d.kind, d.begin, v.kind, v.begin)
+		case strings.HasPrefix(v.begin, d.begin) || strings.HasPrefix(d.begin, v.begin):
+			return ds, fmt.Errorf("%s %q is ambiguous with %s %q", d.kind, d.begin, v.kind, v.begin)
+		}
+	}
+	return append(ds, d), nil
+}
+
+// lineCommentType returns the token type of a line comment that begins with
+// s: // and # are C++ and shell comments, so that their Keep settings apply.
+func lineCommentType(s string) tokenType {
+	switch s {
+	case "//":
+		return tokenCPPComment
+	case "#":
+		return tokenShellComment
+	}
+	return tokenLineComment
+}
+
+// blockCommentType returns the token type of a block comment that begins
+// with begin and ends with end: /* */ is a C comment.
+func blockCommentType(begin, end string) tokenType {
+	if begin == "/*" && end == "*/" {
+		return tokenCComment
+	}
+	return tokenBlockComment
+}
+
+// lexDelimiter lexes the comment or quoted text that starts at the current
+// position with d. If it isn't terminated, false is returned.
+func (l *lexer) lexDelimiter(d *delimiter) bool {
+	switch d.kind {
+	case lineDelim:
+		if l.profile.has(textProfile) {
+			l.lexLineComment(d.typ)
+			return true
+		}
+		// unlike a profile's line comments, the EOL is part of the comment:
+		// the default rules, and delimiters added to a Stripper, have always
+		// elided it with the comment, and callers rely on that output
+		l.emitText()
+		if i := bytes.IndexByte(l.input[l.pos:], nl); i >= 0 {
+			l.pos += Pos(i + 1)
+		} else {
+			l.pos = Pos(len(l.input))
+		}
+		l.emit(d.typ)
+		return true
+	case blockDelim:
+		if d.nested {
+			return l.lexNested(d.begin, d.end, d.typ)
+		}
+		return l.lexDelimited(d.begin, d.end, d.typ)
+	}
+	l.emitText()
+	l.pos += Pos(len(d.begin))
+	for int(l.pos) < len(l.input) {
+		switch {
+		case d.escape == d.end && l.hasPrefix(d.end+d.end):
+			l.pos += Pos(2 * len(d.end))
+		case d.escape != "" && d.escape != d.end && l.hasPrefix(d.escape):
+			l.pos += Pos(len(d.escape))
+			if int(l.pos) < len(l.input) {
+				l.pos++
+			}
+		case l.hasPrefix(d.end):
+			l.pos += Pos(len(d.end))
+			l.emit(d.typ)
+			return true
+		default:
+			l.pos++
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestAddDelimiters(t *testing.T) {
+	var s Stripper
+	if err := s.AddLineComment(";"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddBlockComment("(*", "*)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddQuote("'", "'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddQuote("\"", "\\"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "a ; b\nc\n", "a c\n", ""},
+		{"block", "a (* b\n c *)d", "a d", ""},
+		{"defaultsUnused", "a // b\n# c /* d */", "a // b\n# c /* d */", ""},
+		{"doubled", "'it''s ; (*' ; a", "'it''s ; (*' ", ""},
+		{"empty quote", "'' ; a", "'' ", ""},
+		{"escaped", "\"a \\\" ; b\" ; c", "\"a \\\" ; b\" ", ""},
+		{"unclosedBlock", "a (* b", "", "index 2: unclosed block comment"},
+		{"unclosedQuote", "a 'b'' ; c", "", "index 2: unterminated quoted string"},
+	}
+	testProfile(t, s, tests)
+
+	s.KeepLineComments = true
+	keep := []profileTest{
+		{"line", "a ; b\n(* c *)", "a ; b\n", ""},
+	}
+	testProfile(t, s, keep)
+
+	var cpp Stripper
+	if err := cpp.AddLineComment("//"); err != nil {
+		t.Fatal(err)
+	}
+	cpp.KeepCPPComments = true
+	keep = []profileTest{
+		{"cpp", "a // b\n# c", "a // b\n# c", ""},
+	}
+	testProfile(t, cpp, keep)
+
+	s.Profile = Shell
+	profile := []profileTest{
+		{"profile", "a ; b # c\n", "a ; b \n", ""},
+	}
+	testProfile(t, s, profile)
+}
+
+func TestAddDelimiterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(s *Stripper) error
+		err  string
+	}{
+		{"emptyLine", func(s *Stripper) error { return s.AddLineComment("") }, "line comment delimiter is empty"},
+		{"emptyEnd", func(s *Stripper) error { return s.AddBlockComment("{", "") }, "block comment delimiter is empty"},
+		{"eol", func(s *Stripper) error { return s.AddLineComment("a\n") }, "line comment \"a\\n\" contains an EOL"},
+		{"conflict", func(s *Stripper) error { return s.AddQuote("--", "") }, "quote \"--\" conflicts with line comment \"--\""},
+		{"ambiguousLonger", func(s *Stripper) error { return s.AddBlockComment("--[[", "]]") }, "block comment \"--[[\" is ambiguous with line comment \"--\""},
+		{"ambiguousShorter", func(s *Stripper) error { return s.AddLineComment("{") }, "line comment \"{\" is ambiguous with block comment \"{-\""},
+		{"escape", func(s *Stripper) error { return s.AddQuote("''", "'") }, "escape \"'\" is ambiguous with quote \"''\""},
+	}
+	for _, test := range tests {
+		var s Stripper
+		if err := s.AddLineComment("--"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddBlockComment("{-", "-}"); err != nil {
+			t.Fatal(err)
+		}
+		err := test.add(&s)
+		if err == nil {
+			t.Errorf("%s: expected error %q, got none", test.name, test.err)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: got %q want %q", test.name, err, test.err)
+		}
+		if len(s.delims) != 2 {
+			t.Errorf("%s: got %d delimiters want 2", test.name, len(s.delims))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Dockerfile is the profile for Dockerfiles: a # only starts a comment when
+// it is the first character on its line, other than whitespace. Comment
+// lines within an instruction that continues over multiple lines are
+// comments too. Parser directives, e.g. # syntax= and # escape=, at the
+// start of the input are not comments. Heredoc bodies, <<EOF, are passed
+// through as is; << is only a heredoc if a word follows it, so the shift in
+// $((1<<2)) isn't one.
+var Dockerfile = &Profile{
+	Name:       "dockerfile",
+	Aliases:    []string{"docker"},
+	Extensions: []string{".dockerfile"},
+	Filenames:  []string{"Dockerfile", "Containerfile"},
+	Hints:      []string{"FROM ", "RUN ", "COPY ", "ENTRYPOINT", "WORKDIR ", "EXPOSE ", "CMD "},
+	lexText:    lexDockerfile,
+	delims:     newMatcher(delimiters{{kind: lineDelim, begin: "#", lineStart: true, typ: tokenShellComment}}),
+}
+
+// dockerDirectives are the parser directives.
+var dockerDirectives = map[string]bool{
+	"check":  true,
+	"escape": true,
+	"syntax": true,
+}
+
+// dockerHeredoc matches a heredoc operator and its word, which may be quoted.
+var dockerHeredoc = regexp.MustCompile(`^<<-?(?:[A-Za-z_]\w*|"[A-Za-z_]\w*"|'[A-Za-z_]\w*')`)
+
+// lexDockerfile lexes Dockerfiles.
+func lexDockerfile(l *lexer) stateFn {
+	// parser directives must precede everything else
+	for int(l.pos) < len(l.input) && l.atDockerDirective() {
+		l.pos = l.lineEnd(l.pos)
+		l.skipEOL()
+	}
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '#' && l.lineComment() != nil:
+			l.lexLineComment(tokenShellComment)
+		case c == '$' && l.hasPrefix("$(("):
+			// << is a shift within arithmetic
+			l.pos++
+			if !l.skipShellArith() {
+				l.pos++
+			}
+		case c == '<' && dockerHeredoc.Match(l.input[l.pos:]):
+			l.shellHeredoc()
+		case c == nl:
+			l.pos++
+			if len(l.heredocs) > 0 {
+				l.lexHeredocs()
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atDockerDirective returns whether the line at the current position is a
+// parser directive: # directive=value.
+func (l *lexer) atDockerDirective() bool {
+	line := strings.TrimSpace(string(l.input[l.pos:l.lineEnd(l.pos)]))
+	if !strings.HasPrefix(line, "#") {
+		return false
+	}
+	i := strings.IndexByte(line, '=')
+	if i < 0 {
+		return false
+	}
+	return dockerDirectives[strings.ToLower(strings.TrimSpace(line[1:i]))]
+}
@@ -0,0 +1,36 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestDockerfile(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "# a\nFROM alpine\n  # b\nRUN ls\n", "\nFROM alpine\n  \nRUN ls\n", ""},
+		{"notLineStart", "RUN echo # not a comment\n", "RUN echo # not a comment\n", ""},
+		{"directives", "# syntax=docker/dockerfile:1\n#escape=`\n# a\nFROM alpine\n", "# syntax=docker/dockerfile:1\n#escape=`\n\nFROM alpine\n", ""},
+		{"directiveAfterComment", "# a\n# syntax=docker/dockerfile:1\nFROM alpine\n", "\n\nFROM alpine\n", ""},
+		{"unknownDirective", "# foo=bar\nFROM alpine\n", "\nFROM alpine\n", ""},
+		{"continuation", "RUN apt-get update && \\\n    # a\n    apt-get install -y git\n", "RUN apt-get update && \\\n    \n    apt-get install -y git\n", ""},
+		{"heredoc", "COPY <<EOF /a.sh\n# not a comment\nEOF\n# b\n", "COPY <<EOF /a.sh\n# not a comment\nEOF\n\n", ""},
+		{"shift", "RUN echo $((1<<2)) $(( a << b ))\n# c\n", "RUN echo $((1<<2)) $(( a << b ))\n\n", ""},
+		{"shiftNumber", "RUN x=1<<2\n# c\n", "RUN x=1<<2\n\n", ""},
+		{"heredocQuoted", "RUN <<'EOF' bash\n# not a comment\nEOF\n# c\n", "RUN <<'EOF' bash\n# not a comment\nEOF\n\n", ""},
+		{"crlf", "# a\r\nFROM alpine\r\n", "\r\nFROM alpine\r\n", ""},
+	}
+	testProfile(t, Stripper{Profile: Dockerfile}, tests)
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// CodeOnly returns the input without its comments, using the rules of p, and
+// with the whitespace they leave behind normalized, so that inputs that only
+// differ in their comments have the same code. Lines that only had comments
+// are removed, as is trailing whitespace on the lines that had comments; a
+// comment between whitespace is removed with the whitespace that follows it,
+// and one between identifiers, e.g. a/**/b, is replaced by a space. CRLF
+// EOLs become LF. All comments are removed, including directives. If p is
+// nil, the default rules are used, except that line comments don't include
+// their EOLs.
+func CodeOnly(input []byte, p *Profile) ([]byte, error) {
+	s := Stripper{Profile: p, TrimTrailingSpace: true, DropEmptyLines: true}
+	tokens, err := lexTokens(input, p)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, len(input))
+	var removed []int
+	after := false
+	for _, t := range tokens {
+		v := t.value
+		if !s.keep(t.typ) {
+			eol := trailingEOL(v)
+			removed, b = appendRemoved(removed, b, v[:len(v)-len(eol)])
+			after = true
+			if eol == "" {
+				continue
+			}
+			v = eol
+		}
+		if after && len(b) > 0 && (b[len(b)-1] == ' ' || b[len(b)-1] == '\t') {
+			v = trimLeadingSpace(v)
+		}
+		// a comment can separate identifiers, which mustn't be joined
+		if after && len(b) > 0 && len(v) > 0 && isIdentByte(b[len(b)-1]) && isIdentByte(v[0]) {
+			b = append(b, ' ')
+		}
+		after = false
+		b = append(b, v...)
+	}
+	return bytes.Replace(s.tidy(b, removed), []byte("\r\n"), []byte("\n"), -1), nil
+}
+
+// EqualIgnoringComments returns whether a and b have the same code once
+// their comments are removed, using the rules of p; see CodeOnly.
+func EqualIgnoringComments(a, b []byte, p *Profile) (bool, error) {
+	ca, err := CodeOnly(a, p)
+	if err != nil {
+		return false, err
+	}
+	cb, err := CodeOnly(b, p)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ca, cb), nil
+}
+
+// trimLeadingSpace returns s without its leading spaces and tabs.
+func trimLeadingSpace(s string) string {
+	i := 0
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return s[i:]
+}
@@ -0,0 +1,75 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestCodeOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *Profile
+		input   string
+		output  string
+	}{
+		{"empty", Go, "", ""},
+		{"comments", Go, "// a\npackage b\n\n\t// c\nvar d = 1 // e\nvar f = g /* h */ + i\n", "package b\n\nvar d = 1\nvar f = g + i\n"},
+		{"noSpace", Go, "a/* b */+c\na /* b */+c\n", "a+c\na +c\n"},
+		{"identifiers", Go, "a/**/b\na/* c */ b\n", "a b\na b\n"},
+		{"crlf", Go, "a // b\r\n// c\r\nd\r\n", "a\nd\n"},
+		{"strings", Go, "a := \"b  c\"  // d\n", "a := \"b  c\"\n"},
+		{"directives", Go, "//go:build a\npackage b\n", "package b\n"},
+		{"default", nil, "a // b\nc # d\n", "a\nc\n"},
+	}
+	for _, test := range tests {
+		code, err := CodeOnly([]byte(test.input), test.profile)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if string(code) != test.output {
+			t.Errorf("%s: got %q want %q", test.name, code, test.output)
+		}
+	}
+}
+
+func TestEqualIgnoringComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{"same", "a = 1\n", "a = 1\n", true},
+		{"added", "a = 1\nb = 2\n", "# a\na = 1 # one\n\nb = 2\n", false},
+		{"comments", "a = 1\n\nb = 2\n", "# a\na = 1 # one\n\n  # b\nb = 2\n", true},
+		{"changed", "a = 1 # one\n", "a = 2 # one\n", false},
+		{"indent", "if a:\n  b\n", "if a:\n    b # c\n", false},
+		{"quoted", "a = '# b'\n", "a = '# c'\n", false},
+		{"crlf", "a = 1\nb = 2\n", "a = 1 # one\r\nb = 2\r\n", true},
+	}
+	for _, test := range tests {
+		equal, err := EqualIgnoringComments([]byte(test.a), []byte(test.b), Python)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if equal != test.equal {
+			t.Errorf("%s: got %v want %v", test.name, equal, test.equal)
+		}
+	}
+	if _, err := EqualIgnoringComments([]byte("a = 'b\n"), nil, Python); err == nil {
+		t.Errorf("expected an error for input that can't be lexed")
+	}
+}
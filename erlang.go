@@ -0,0 +1,51 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// Erlang is the profile for Erlang: % line comments. Strings are delimited
+// by " and quoted atoms by '; both use backslash escapes. Character
+// literals, $% and $", are not comments or strings.
+var Erlang = &Profile{
+	Name:         "erlang",
+	Extensions:   []string{".erl", ".hrl", ".escript"},
+	Interpreters: []string{"escript"},
+	Hints:        []string{"-module(", "-export(", "->", "end.", "io:format"},
+	lexText:      lexErlang,
+}
+
+// lexErlang lexes Erlang.
+func lexErlang(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '%':
+			l.lexLineComment(tokenLineComment)
+		case c == '"' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '$':
+			// a character literal: $c or $\c
+			l.pos++
+			if l.hasPrefix("\\") {
+				l.pos++
+			}
+			if int(l.pos) < len(l.input) {
+				l.pos++
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
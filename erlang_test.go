@@ -0,0 +1,30 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestErlang(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "%% a\n-module(a). % b\n", "\n-module(a). \n", ""},
+		{"string", "f() -> \"100% \\\" %\". % a\n", "f() -> \"100% \\\" %\". \n", ""},
+		{"atom", "f() -> '%'. % a\n", "f() -> '%'. \n", ""},
+		{"char", "f() -> [$%, $\", $\\%]. % a\n", "f() -> [$%, $\", $\\%]. \n", ""},
+		{"unclosedString", "f() -> \"a", "", "index 7: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Erlang}, tests)
+}
@@ -0,0 +1,100 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// Go is the profile for Go: // and /* */ comments. Interpreted strings and
+// rune literals use backslash escapes; raw strings, delimited by back
+// quotes, have none.
+//
+// Directives, e.g. //go:build, // +build, //line, and //export, and the cgo
+// preamble, the comment that immediately precedes import "C", are directive
+// comments.
+var Go = &Profile{
+	Name:       "go",
+	Aliases:    []string{"golang"},
+	Extensions: []string{".go"},
+	Hints:      []string{"package ", "func ", ":= ", "import (", "fmt.", "err != nil"},
+	lexText:    lexGo,
+}
+
+// goDirectives are the prefixes of line comments that are directives.
+var goDirectives = []string{"//go:", "// +build", "//line ", "//export ", "//extern "}
+
+// lexGo lexes Go.
+func lexGo(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '/' && l.hasPrefix("//"):
+			typ := tokenCPPComment
+			if l.atGoDirective() || l.precedesImportC(l.lineEnd(l.pos)) {
+				typ = tokenDirectiveComment
+			}
+			l.lexLineComment(typ)
+		case c == '/' && l.hasPrefix("/*"):
+			typ := tokenCComment
+			if i := bytes.Index(l.input[l.pos+2:], []byte("*/")); i >= 0 && l.precedesImportC(l.pos+Pos(i+4)) {
+				typ = tokenDirectiveComment
+			}
+			if !l.lexDelimited("/*", "*/", typ) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '"' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '`':
+			if !l.lexQuoted(c, 0, false) {
+				return l.errorf("unterminated raw string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atGoDirective returns whether the line comment at the current position is
+// a directive.
+func (l *lexer) atGoDirective() bool {
+	for _, d := range goDirectives {
+		if l.hasPrefix(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// precedesImportC returns whether the comment that ends at pos is part of a
+// cgo preamble: it is followed by import "C", with only line comments
+// between them.
+func (l *lexer) precedesImportC(pos Pos) bool {
+	for {
+		switch {
+		case bytes.HasPrefix(l.input[pos:], []byte("\r\n")):
+			pos += 2
+		case bytes.HasPrefix(l.input[pos:], []byte("\n")):
+			pos++
+		default:
+			return false
+		}
+		if !bytes.HasPrefix(l.input[pos:], []byte("//")) {
+			return bytes.HasPrefix(l.input[pos:], []byte(`import "C"`))
+		}
+		pos = l.lineEnd(pos)
+	}
+}
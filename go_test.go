@@ -0,0 +1,40 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestGo(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "package a // b\n/* c */\nvar d = 1 /* e\n f */\n", "package a \n\nvar d = 1 \n", ""},
+		{"strings", "a := \"// b \\\" /*\" + `/* c\n\\` + '\\'' // d\n", "a := \"// b \\\" /*\" + `/* c\n\\` + '\\'' \n", ""},
+		{"rune", "a := '\"' // b\n", "a := '\"' \n", ""},
+		{"directives", "//go:build linux\n// +build linux\n\n// a\npackage b\n//go:generate c\n", "\n\n\n\npackage b\n\n", ""},
+		{"cgo", "// a\n\n// #include <b.h>\n// int c;\nimport \"C\"\n", "\n\n\n\nimport \"C\"\n", ""},
+		{"cgoBlock", "/*\n#include <a.h>\n*/\nimport \"C\" // b\n", "\nimport \"C\" \n", ""},
+		{"unclosedComment", "a /* b", "", "index 2: unclosed block comment"},
+		{"unclosedRaw", "a := `b", "", "index 5: unterminated raw string"},
+	}
+	testProfile(t, Stripper{Profile: Go}, tests)
+
+	keep := []profileTest{
+		{"directives", "//go:build linux\n\n// a\npackage b //line c.go:1\n", "//go:build linux\n\n\npackage b //line c.go:1\n", ""},
+		{"cgo", "// a\n\n// #include <b.h>\n// int c;\nimport \"C\"\n", "\n\n// #include <b.h>\n// int c;\nimport \"C\"\n", ""},
+		{"cgoBlock", "/*\n#include <a.h>\n*/\nimport \"C\" // b\n", "/*\n#include <a.h>\n*/\nimport \"C\" \n", ""},
+	}
+	testProfile(t, Stripper{Profile: Go, KeepDirectiveComments: true}, keep)
+}
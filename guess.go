@@ -0,0 +1,98 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// guessSample is the most input GuessProfile examines.
+const guessSample = 64 << 10
+
+// GuessProfile returns the registered profile that the input is most likely
+// in and the confidence, from 0 to 1, of the guess. It is meant for input
+// whose filename isn't known; see DetectProfile.
+//
+// A #! line or modeline is trusted: the confidence is 1. Otherwise each
+// profile that lexes the input without error is scored by how many of its
+// hints the input contains, and a little more if its comment delimiters
+// appear in the input. The confidence is the best score's share of all the
+// scores, so it is lower when other profiles are about as likely, scaled down
+// when the best score is low, as there is little evidence for it. If no
+// profile's hints appear in the input, nil and 0 are returned.
+func GuessProfile(input []byte) (*Profile, float64) {
+	if p := DetectProfile("", input); p != nil {
+		return p, 1
+	}
+	sample, truncated := input, false
+	if len(sample) > guessSample {
+		sample, truncated = sample[:guessSample], true
+		if i := bytes.LastIndexByte(sample, nl); i > 0 {
+			sample = sample[:i+1]
+		}
+	}
+	registry.RLock()
+	profiles := append([]*Profile(nil), registry.profiles...)
+	registry.RUnlock()
+	var best *Profile
+	var bestScore, total float64
+	for _, p := range profiles {
+		score := guessScore(p, sample, truncated)
+		if score > bestScore {
+			best, bestScore = p, score
+		}
+		total += score
+	}
+	if best == nil || bestScore < 1 {
+		return nil, 0
+	}
+	return best, bestScore / total * bestScore / (bestScore + 1)
+}
+
+// guessScore returns how likely it is that the sample is in the language of
+// the profile: a point for each of its hints that the sample contains and
+// half a point if the sample has comments. A profile that can't lex the
+// sample scores 0, unless the sample is truncated as the error may be due to
+// the truncation.
+func guessScore(p *Profile, sample []byte, truncated bool) float64 {
+	var score float64
+	for _, h := range p.Hints {
+		if bytes.Contains(sample, []byte(h)) {
+			score++
+		}
+	}
+	if score == 0 {
+		return 0
+	}
+	comments := false
+	l := lexProfile(sample, p)
+	for {
+		t := l.nextToken()
+		switch t.typ {
+		case tokenEOF:
+			if comments {
+				score += 0.5
+			}
+			return score
+		case tokenError:
+			if truncated {
+				return score
+			}
+			return 0
+		case tokenText, tokenQuotedText:
+		default:
+			comments = true
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGuessProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		profile *Profile
+	}{
+		{"empty", "", nil},
+		{"text", "Nothing to see here.\n", nil},
+		{"shebang", "#!/bin/sh\necho a\n", Shell},
+		{"modeline", "# vim: ft=ruby\na\n", Ruby},
+		{"go", "package main\n\nimport \"fmt\"\n\n// a\nfunc main() {\n\tb := 1\n\tfmt.Println(b)\n}\n", Go},
+		{"python", "import os\n\ndef a(b):\n    # c\n    if b is None:\n        return self.d\n", Python},
+		{"sql", "-- a\nSELECT b FROM c WHERE d = 'e' ORDER BY f;\n", SQL},
+		{"html", "<!DOCTYPE html>\n<html>\n<head><title>a</title></head>\n<!-- b -->\n<body><div>c</div></body>\n</html>\n", HTML},
+		{"php", "<?php\n// a\n$b = 1;\necho $b;\n", PHP},
+		{"rust", "use std::io;\n\n// a\nfn main() {\n    let mut b = String::new();\n}\n", Rust},
+	}
+	for _, test := range tests {
+		p, confidence := GuessProfile([]byte(test.input))
+		if p != test.profile {
+			t.Errorf("%s: got %v want %v", test.name, profileName(p), profileName(test.profile))
+			continue
+		}
+		if p == nil && confidence != 0 {
+			t.Errorf("%s: got confidence %v want 0", test.name, confidence)
+		}
+		if p != nil && (confidence <= 0 || confidence > 1) {
+			t.Errorf("%s: confidence %v out of range", test.name, confidence)
+		}
+	}
+}
+
+func TestGuessProfileLexError(t *testing.T) {
+	// the unclosed raw string rules out Go, which would otherwise win
+	input := "package main\n\nfunc main() {\n\ta := `b\n}\n"
+	if p, _ := GuessProfile([]byte(input)); p == Go {
+		t.Errorf("got %v, which can't lex the input", profileName(p))
+	}
+	// unless the input is truncated, as the error may be due to that
+	input = "package main\n\nfunc main() {\n" + strings.Repeat("\ta := 1\n", guessSample/8) + "\tb := `c\n}\n"
+	if p, _ := GuessProfile([]byte(input)); p != Go {
+		t.Errorf("truncated: got %v want %v", profileName(p), Go.Name)
+	}
+}
+
+func TestGuessProfileConfidence(t *testing.T) {
+	_, shebang := GuessProfile([]byte("#!/usr/bin/env python3\n"))
+	if shebang != 1 {
+		t.Errorf("shebang: got %v want 1", shebang)
+	}
+	_, weak := GuessProfile([]byte("a = 1\nprint(a)\n"))
+	_, strong := GuessProfile([]byte("import os\n\ndef a(b):\n    # c\n    if b is None:\n        return self.d\n"))
+	if weak >= strong {
+		t.Errorf("got confidence %v for weak evidence, %v for strong", weak, strong)
+	}
+}
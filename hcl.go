@@ -0,0 +1,125 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// HCL is the profile for HCL and Terraform: #, //, and /* */ comments.
+// Strings are delimited by " and use backslash escapes; they may contain
+// ${} interpolations and %{} directives, which may contain strings of their
+// own. Heredoc bodies, <<EOT and <<-EOT, are passed through as is.
+var HCL = &Profile{
+	Name:       "hcl",
+	Aliases:    []string{"terraform"},
+	Extensions: []string{".hcl", ".tf", ".tfvars"},
+	Hints:      []string{"resource \"", "variable \"", "provider \"", "module \"", "terraform {", "output \""},
+	lexText:    lexHCL,
+}
+
+// lexHCL lexes HCL.
+func lexHCL(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '#':
+			l.lexLineComment(tokenShellComment)
+		case c == '/' && l.hasPrefix("//"):
+			l.lexLineComment(tokenCPPComment)
+		case c == '/' && l.hasPrefix("/*"):
+			if !l.lexDelimited("/*", "*/", tokenCComment) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '"':
+			l.emitText()
+			if !l.skipHCLString() {
+				return l.errorf("unterminated quoted string")
+			}
+			l.emit(tokenQuotedText)
+		case c == '<' && l.hasPrefix("<<"):
+			l.hclHeredoc()
+		case c == nl:
+			l.pos++
+			if len(l.heredocs) > 0 {
+				l.lexHeredocs()
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// skipHCLString consumes a string, including any interpolations and
+// directives within it. If the string isn't terminated, false is returned.
+func (l *lexer) skipHCLString() bool {
+	l.pos++
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '\\':
+			l.pos += 2
+		case c == '"':
+			l.pos++
+			return true
+		case l.hasPrefix("$${") || l.hasPrefix("%%{"):
+			// an escaped interpolation or directive
+			l.pos += 3
+		case l.hasPrefix("${") || l.hasPrefix("%{"):
+			l.pos += 2
+			if !l.skipHCLTemplateExpr() {
+				return false
+			}
+		default:
+			l.pos++
+		}
+	}
+	return false
+}
+
+// skipHCLTemplateExpr consumes the expression within an interpolation or a
+// directive, including the closing }.
+func (l *lexer) skipHCLTemplateExpr() bool {
+	depth := 0
+	for int(l.pos) < len(l.input) {
+		switch l.input[l.pos] {
+		case '"':
+			if !l.skipHCLString() {
+				return false
+			}
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				l.pos++
+				return true
+			}
+			depth--
+		}
+		l.pos++
+	}
+	return false
+}
+
+// hclHeredoc processes a heredoc operator, <<EOT or <<-EOT. The body is
+// consumed once the rest of the line has been lexed.
+func (l *lexer) hclHeredoc() {
+	l.pos += 2
+	if l.hasPrefix("-") {
+		l.pos++
+	}
+	i := l.pos
+	for int(l.pos) < len(l.input) && isIdentByte(l.input[l.pos]) {
+		l.pos++
+	}
+	if l.pos > i {
+		l.addHeredoc(string(l.input[i:l.pos]), " \t")
+	}
+}
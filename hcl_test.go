@@ -0,0 +1,35 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestHCL(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "# a\n// b\n/* c */a = 1 # d\n", "\n\na = 1 \n", ""},
+		{"string", "a = \"# // /* \\\" \" # b\n", "a = \"# // /* \\\" \" \n", ""},
+		{"interpolation", "a = \"${ \"#\" }-${var.b}\" # c\n", "a = \"${ \"#\" }-${var.b}\" \n", ""},
+		{"interpolationNested", "a = \"${ lookup({\"k\" = \"}#\"}, \"k\") }//\" // b\n", "a = \"${ lookup({\"k\" = \"}#\"}, \"k\") }//\" \n", ""},
+		{"directive", "a = \"%{ if var.b }# %{ endif }\" # c\n", "a = \"%{ if var.b }# %{ endif }\" \n", ""},
+		{"escapedInterpolation", "a = \"$${\" # b\n", "a = \"$${\" \n", ""},
+		{"heredoc", "a = <<EOT\n# not a comment\n${ \"x\" } // nor this\nEOT\n# b\n", "a = <<EOT\n# not a comment\n${ \"x\" } // nor this\nEOT\n\n", ""},
+		{"heredocIndented", "a = <<-EOT\n    # not a comment\n    EOT\n# b\n", "a = <<-EOT\n    # not a comment\n    EOT\n\n", ""},
+		{"unclosedString", "a = \"${b", "", "index 4: unterminated quoted string"},
+		{"unclosedComment", "a = 1 /* b", "", "index 6: unclosed block comment"},
+	}
+	testProfile(t, Stripper{Profile: HCL}, tests)
+}
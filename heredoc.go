@@ -0,0 +1,65 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// heredoc is a heredoc whose operator has been processed but whose body has
+// not: the body starts on the line following the operator.
+type heredoc struct {
+	delim  string // the line that terminates the body
+	indent string // the characters that may precede delim on its line
+}
+
+// addHeredoc queues a heredoc; its body will be consumed by lexHeredocs.
+func (l *lexer) addHeredoc(delim, indent string) {
+	l.heredocs = append(l.heredocs, heredoc{delim: delim, indent: indent})
+}
+
+// lexHeredocs consumes the bodies, including the terminating lines, of all
+// queued heredocs. The current position must be the start of the line that
+// follows the operators. Each body is emitted as quoted text as nothing in it
+// is a comment. A body without a terminating line runs to EOF.
+func (l *lexer) lexHeredocs() {
+	l.emitText()
+	for _, h := range l.heredocs {
+		for int(l.pos) < len(l.input) {
+			end := l.lineEnd(l.pos)
+			line := l.input[l.pos:end]
+			if h.indent != "" {
+				line = bytes.TrimLeft(line, h.indent)
+			}
+			l.pos = end
+			l.skipEOL()
+			if string(line) == h.delim {
+				break
+			}
+		}
+		l.emit(tokenQuotedText)
+	}
+	l.heredocs = l.heredocs[:0]
+}
+
+// skipEOL consumes the EOL, \n or \r\n, at the current position, if any.
+func (l *lexer) skipEOL() {
+	if l.hasPrefix("\r\n") {
+		l.pos += 2
+		return
+	}
+	if l.hasPrefix("\n") {
+		l.pos++
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// markup flags.
+const (
+	markupXML profileFlag = 1 << iota
+	markupPHP
+)
+
+// HTML is the profile for HTML: <!-- --> comments. CDATA sections, attribute
+// values, and the content of pre, textarea, and title elements are left
+// alone. The content of script elements is lexed using the JavaScript
+// profile and the content of style elements using the CSS profile; scripts
+// whose type isn't JavaScript are left alone.
+//
+// Conditional comments, <!--[if IE]> and <!--<![endif]-->, are directive
+// comments.
+var HTML = &Profile{
+	Name:       "html",
+	Aliases:    []string{"xhtml"},
+	Extensions: []string{".html", ".htm", ".xhtml"},
+	Hints:      []string{"<!DOCTYPE html", "<html", "<div", "<head>", "<body", "</p>", "<script"},
+	lexText:    lexMarkup,
+}
+
+// XML is the profile for XML: <!-- --> comments. CDATA sections, attribute
+// values, and processing instructions are left alone.
+var XML = &Profile{
+	Name:       "xml",
+	Aliases:    []string{"nxml", "xsd", "xslt"},
+	Extensions: []string{".xml", ".xsd", ".xsl", ".xslt", ".svg", ".plist"},
+	Hints:      []string{"<?xml", "</", "/>", "xmlns"},
+	lexText:    lexMarkup,
+	flags:      markupXML,
+}
+
+// lexMarkup lexes HTML and XML.
+func lexMarkup(l *lexer) stateFn {
+	html := !l.profile.has(markupXML)
+	php := l.profile.has(markupPHP)
+	for int(l.pos) < len(l.input) {
+		if l.input[l.pos] != '<' {
+			l.pos++
+			continue
+		}
+		switch {
+		case php && isPHPOpenTag(l.input[l.pos:]):
+			return lexPHP
+		case php && l.hasPrefix("<!--") && l.commentHasPHP():
+			// the PHP code in it is still run: the comment is left alone
+			l.pos += 4
+		case l.hasPrefix("<!--"):
+			typ := tokenBlockComment
+			if l.hasPrefix("<!--[if") || l.hasPrefix("<!--<![endif]") {
+				typ = tokenDirectiveComment
+			}
+			if !l.lexDelimited("<!--", "-->", typ) {
+				return l.errorf("unclosed block comment")
+			}
+		case l.hasPrefix("<![CDATA["):
+			if !l.lexDelimited("<![CDATA[", "]]>", tokenQuotedText) {
+				return l.errorf("unterminated CDATA section")
+			}
+		case l.hasPrefix("<?"):
+			// processing instruction: it's code
+			i := bytes.Index(l.input[l.pos:], []byte("?>"))
+			if i < 0 {
+				l.pos = Pos(len(l.input))
+				continue
+			}
+			l.pos += Pos(i + 2)
+		case l.atTagStart():
+			name, attrs, err := l.lexTag()
+			if err != nil {
+				return l.errorf("%s", err)
+			}
+			if !html || name == "" {
+				continue
+			}
+			end := l.indexEndTag(name)
+			if php && containsPHP(l.input[l.pos:end]) {
+				// PHP code may generate any of it
+				l.pos = end
+				continue
+			}
+			switch name {
+			case "script":
+				if !isJavaScriptType(attrs["type"]) {
+					l.pos = end
+					continue
+				}
+				if !l.lexEmbedded(JavaScript, end, "</script") {
+					return nil
+				}
+			case "style":
+				if !l.lexEmbedded(CSS, end, "</style") {
+					return nil
+				}
+			case "pre", "textarea", "title":
+				l.pos = end
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atTagStart returns whether a start or end tag, or a declaration like
+// <!DOCTYPE>, starts at the current position.
+func (l *lexer) atTagStart() bool {
+	i := int(l.pos) + 1
+	if i < len(l.input) && (l.input[i] == '/' || l.input[i] == '!') {
+		i++
+	}
+	if i >= len(l.input) {
+		return false
+	}
+	c := l.input[i]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == ':'
+}
+
+// lexTag consumes a tag. Quoted attribute values are emitted as quoted text,
+// except for any PHP code in them. For start tags that aren't self-closing,
+// the lowercased element name and the attributes are returned; otherwise name
+// is empty. If an attribute value isn't terminated, or its PHP code can't be
+// lexed, an error is returned.
+func (l *lexer) lexTag() (name string, attrs map[string]string, err error) {
+	l.pos++
+	start := true
+	if l.hasPrefix("/") || l.hasPrefix("!") {
+		start = false
+		l.pos++
+	}
+	i := l.pos
+	for int(l.pos) < len(l.input) && !isTagDelim(l.input[l.pos]) {
+		l.pos++
+	}
+	name = strings.ToLower(string(l.input[i:l.pos]))
+	attrs = make(map[string]string)
+	var attr string // the attribute being processed
+	var eq bool     // whether attr's = has been processed
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '>':
+			l.pos++
+			if !start {
+				return "", attrs, nil
+			}
+			return name, attrs, nil
+		case c == '/' && l.hasPrefix("/>"):
+			l.pos += 2
+			return "", attrs, nil
+		case c == '[' && !start:
+			// a declaration's internal subset is markup, it's lexed as such
+			l.pos++
+			return "", attrs, nil
+		case c == '"' || c == '\'':
+			i := l.pos
+			if err := l.lexAttrValue(c); err != nil {
+				return "", attrs, err
+			}
+			if eq {
+				attrs[attr] = string(l.input[i+1 : l.pos-1])
+			}
+			attr, eq = "", false
+		case c == '=':
+			eq = attr != ""
+			l.pos++
+		case isTagDelim(c):
+			l.pos++
+		default:
+			// an attribute name or an unquoted value
+			i := l.pos
+			for int(l.pos) < len(l.input) && !isTagDelim(l.input[l.pos]) && l.input[l.pos] != '=' {
+				l.pos++
+			}
+			if eq {
+				attrs[attr] = string(l.input[i:l.pos])
+				attr, eq = "", false
+				continue
+			}
+			attr = strings.ToLower(string(l.input[i:l.pos]))
+		}
+	}
+	return "", attrs, nil
+}
+
+// lexAttrValue consumes an attribute value quoted with quote and emits it as
+// quoted text. With the PHP profile, PHP code in the value is lexed as such.
+func (l *lexer) lexAttrValue(quote byte) error {
+	if !l.profile.has(markupPHP) {
+		if !l.lexQuoted(quote, 0, false) {
+			return errors.New("unterminated quoted string")
+		}
+		return nil
+	}
+	l.emitText()
+	for l.pos++; int(l.pos) < len(l.input); {
+		switch {
+		case l.input[l.pos] == quote:
+			l.pos++
+			l.emit(tokenQuotedText)
+			return nil
+		case isPHPOpenTag(l.input[l.pos:]):
+			l.emit(tokenQuotedText)
+			if err := l.lexPHPCode(); err != nil {
+				return err
+			}
+		default:
+			l.pos++
+		}
+	}
+	return errors.New("unterminated quoted string")
+}
+
+// indexEndTag returns the position of the end tag of the named element,
+// which is matched without regard to case. If there isn't one, the length
+// of the input is returned.
+func (l *lexer) indexEndTag(name string) Pos {
+	for i := int(l.pos); ; i += 2 {
+		j := bytes.Index(l.input[i:], []byte("</"))
+		if j < 0 {
+			return Pos(len(l.input))
+		}
+		i += j
+		k := i + 2 + len(name)
+		if k <= len(l.input) && bytes.EqualFold(l.input[i+2:k], []byte(name)) &&
+			(k == len(l.input) || isTagDelim(l.input[k])) {
+			return Pos(i)
+		}
+	}
+}
+
+// isTagDelim returns whether c ends a tag or attribute name.
+func isTagDelim(c byte) bool {
+	return c == '>' || c == '/' || isSpace(c)
+}
+
+// isSpace returns whether c is ASCII whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == nl || c == cr || c == '\f' || c == '\v'
+}
+
+// isJavaScriptType returns whether the type attribute of a script element
+// denotes JavaScript.
+func isJavaScriptType(typ string) bool {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	return typ == "" || typ == "module" || strings.Contains(typ, "javascript") || strings.Contains(typ, "ecmascript")
+}
@@ -0,0 +1,62 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "<p>a<!-- b\n c -->d</p>", "<p>ad</p>", ""},
+		{"conditional", "<!--[if IE]><p>IE</p><![endif]--><!--[if !IE]><!--><p>x</p><!--<![endif]-->", "<p>x</p>", ""},
+		{"cdata", "<![CDATA[ <!-- a --> ]]><!-- b -->", "<![CDATA[ <!-- a --> ]]>", ""},
+		{"attribute", "<a title=\"<!-- a -->\" alt='<!--'>x</a><!-- b -->", "<a title=\"<!-- a -->\" alt='<!--'>x</a>", ""},
+		{"text", "<p>it's \"a\" <!-- b --></p>", "<p>it's \"a\" </p>", ""},
+		{"pre", "<PRE><!-- a --></pre><!-- b -->", "<PRE><!-- a --></pre>", ""},
+		{"textarea", "<textarea><!-- a --></textarea>", "<textarea><!-- a --></textarea>", ""},
+		{"doctype", "<!DOCTYPE html><!-- a --><html>", "<!DOCTYPE html><html>", ""},
+		{"script", "<script>var a = \"<!-- x // y\"; // b\n/* c */</script><!-- d -->", "<script>var a = \"<!-- x // y\"; \n</script>", ""},
+		{"scriptType", "<script type=\"text/javascript\">a(); // b\n</script>", "<script type=\"text/javascript\">a(); \n</script>", ""},
+		{"scriptModule", "<script type=module>a(); // b\n</script>", "<script type=module>a(); \n</script>", ""},
+		{"scriptTemplate", "<script type=\"text/x-template\"><!-- a --> // b</script>", "<script type=\"text/x-template\"><!-- a --> // b</script>", ""},
+		{"scriptSrc", "<script src=\"a.js\"></script><!-- a -->", "<script src=\"a.js\"></script>", ""},
+		{"scriptUnclosed", "<script>a(); /* b */", "<script>a(); ", ""},
+		{"style", "<style>p { color: red; /* b */ content: \"/* c */\"; }</STYLE>", "<style>p { color: red;  content: \"/* c */\"; }</STYLE>", ""},
+		{"selfClosing", "<br/><!-- a --><img src=x />", "<br/><img src=x />", ""},
+		{"lessThan", "a < b<!-- c -->", "a < b", ""},
+		{"unclosedComment", "<p><!-- a", "", "index 3: unclosed block comment"},
+		{"unclosedCDATA", "<![CDATA[ a", "", "index 0: unterminated CDATA section"},
+		{"unclosedAttribute", "<a href=\"x>", "", "index 8: unterminated quoted string"},
+		{"unclosedScriptComment", "<script>/* a</script>", "", "index 8: unclosed block comment"},
+	}
+	testProfile(t, Stripper{Profile: HTML}, tests)
+
+	keep := []profileTest{
+		{"conditional", "<!--[if IE]><p>IE</p><![endif]--><!-- a -->", "<!--[if IE]><p>IE</p><![endif]-->", ""},
+	}
+	testProfile(t, Stripper{Profile: HTML, KeepDirectiveComments: true}, keep)
+}
+
+func TestXML(t *testing.T) {
+	tests := []profileTest{
+		{"comment", "<?xml version=\"1.0\"?>\n<!-- a -->\n<a b=\"<!--\"/>", "<?xml version=\"1.0\"?>\n\n<a b=\"<!--\"/>", ""},
+		{"cdata", "<a><![CDATA[<!-- a -->]]></a>", "<a><![CDATA[<!-- a -->]]></a>", ""},
+		{"pi", "<?pi <!-- a --> ?><b/>", "<?pi <!-- a --> ?><b/>", ""},
+		{"script", "<script>// <!-- a --></script>", "<script>// </script>", ""},
+		{"doctype", "<!DOCTYPE a [\n<!-- a -->\n<!ELEMENT a (#PCDATA)>\n]><a/>", "<!DOCTYPE a [\n\n<!ELEMENT a (#PCDATA)>\n]><a/>", ""},
+	}
+	testProfile(t, Stripper{Profile: XML}, tests)
+}
@@ -0,0 +1,248 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// JavaScript is the profile for JavaScript: // and /* */ comments. Strings
+// are delimited by " or ' and use backslash escapes. Template literals may
+// contain ${} substitutions, which are lexed as code and may themselves
+// contain strings, comments, and template literals. Whether a / starts a
+// regular expression or is division is determined by the previous token; after
+// the ) that closes an if, while, for, or with condition, it is a regular
+// expression.
+//
+// Legal comments, /*! */, are kept if Stripper.KeepLegalComments is set.
+// Source map comments, //# sourceMappingURL= and //# sourceURL=, are
+// directive comments. A #! line at the start of the input is not a comment.
+var JavaScript = &Profile{
+	Name:         "javascript",
+	Aliases:      []string{"js", "javascriptreact"},
+	Extensions:   []string{".js", ".mjs", ".cjs", ".jsx"},
+	Interpreters: []string{"node", "nodejs"},
+	Hints:        []string{"function ", "const ", "let ", "=> ", "console.log", "require(", "module.exports", "document."},
+	lexText:      lexJavaScript,
+}
+
+// TypeScript is the profile for TypeScript. Its comment and quoting rules are
+// those of JavaScript.
+var TypeScript = &Profile{
+	Name:         "typescript",
+	Aliases:      []string{"ts", "typescriptreact"},
+	Extensions:   []string{".ts", ".mts", ".cts", ".tsx"},
+	Interpreters: []string{"ts-node", "deno"},
+	Hints:        []string{": string", ": number", ": boolean", "interface ", "import {", "=> ", "const ", "readonly "},
+	lexText:      lexJavaScript,
+}
+
+// regexpKeywords are the keywords that may be followed by a regular
+// expression; after any other identifier a / is division.
+var regexpKeywords = map[string]bool{
+	"await":      true,
+	"case":       true,
+	"delete":     true,
+	"do":         true,
+	"else":       true,
+	"in":         true,
+	"instanceof": true,
+	"new":        true,
+	"of":         true,
+	"return":     true,
+	"throw":      true,
+	"typeof":     true,
+	"void":       true,
+	"yield":      true,
+}
+
+// conditionKeywords are the keywords whose parenthesized condition may be
+// followed by a statement, e.g. if (x) /re/.test(s).
+var conditionKeywords = map[string]bool{
+	"for":   true,
+	"if":    true,
+	"while": true,
+	"with":  true,
+}
+
+// lexJavaScript lexes JavaScript and TypeScript.
+func lexJavaScript(l *lexer) stateFn {
+	if l.pos == 0 && l.hasPrefix("#!") {
+		l.pos = l.lineEnd(l.pos)
+	}
+	regexp := true   // whether a / starts a regular expression
+	var depth int    // nesting depth of {}
+	var subst []int  // the depth at which each open ${} substitution started
+	var conds []bool // whether each open ( starts an if, while, for, or with condition
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case isSpace(c):
+			l.pos++
+		case c == '/' && l.hasPrefix("//"):
+			typ := tokenCPPComment
+			if l.atSourceMapComment() {
+				typ = tokenDirectiveComment
+			}
+			l.lexLineComment(typ)
+		case c == '/' && l.hasPrefix("/*"):
+			typ := tokenCComment
+			if l.hasPrefix("/*!") {
+				typ = tokenLegalComment
+			}
+			if !l.lexDelimited("/*", "*/", typ) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '/' && regexp:
+			if !l.lexRegexp() {
+				return l.errorf("unterminated regular expression")
+			}
+			regexp = false
+		case c == '"' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+			regexp = false
+		case c == '`' || (c == '}' && len(subst) > 0 && subst[len(subst)-1] == depth):
+			if c == '}' {
+				subst = subst[:len(subst)-1]
+			}
+			open, ok := l.lexTemplate()
+			if !ok {
+				return l.errorf("unterminated template literal")
+			}
+			if open {
+				subst = append(subst, depth)
+			}
+			regexp = open
+		case c == '{':
+			depth++
+			l.pos++
+			regexp = true
+		case c == '}':
+			depth--
+			l.pos++
+			regexp = true
+		case c == '(':
+			conds = append(conds, conditionKeywords[l.precedingIdent()])
+			l.pos++
+			regexp = true
+		case c == ')':
+			// a statement, not a value, may follow a condition
+			regexp = false
+			if len(conds) > 0 {
+				regexp = conds[len(conds)-1]
+				conds = conds[:len(conds)-1]
+			}
+			l.pos++
+		case c == ']':
+			l.pos++
+			regexp = false
+		case (c == '+' && l.hasPrefix("++")) || (c == '-' && l.hasPrefix("--")):
+			// the operand determines what may follow
+			l.pos += 2
+		case isIdentByte(c):
+			i := l.pos
+			for int(l.pos) < len(l.input) && isIdentByte(l.input[l.pos]) {
+				l.pos++
+			}
+			regexp = regexpKeywords[string(l.input[i:l.pos])]
+		default:
+			l.pos++
+			regexp = true
+		}
+	}
+	return lexEOF
+}
+
+// atSourceMapComment returns whether the line comment at the current
+// position is a source map comment.
+func (l *lexer) atSourceMapComment() bool {
+	for _, s := range []string{"//# sourceMappingURL=", "//# sourceURL=", "//@ sourceMappingURL=", "//@ sourceURL="} {
+		if l.hasPrefix(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// precedingIdent returns the identifier that precedes the current position,
+// ignoring whitespace. If something else precedes it, an empty string is
+// returned.
+func (l *lexer) precedingIdent() string {
+	i := int(l.pos)
+	for i > 0 && isSpace(l.input[i-1]) {
+		i--
+	}
+	j := i
+	for j > 0 && isIdentByte(l.input[j-1]) {
+		j--
+	}
+	return string(l.input[j:i])
+}
+
+// lexRegexp consumes a regular expression literal, including its flags, and
+// emits it as quoted text. A / within a character class doesn't end the
+// literal. If the literal isn't terminated before the EOL, false is returned.
+func (l *lexer) lexRegexp() bool {
+	l.emitText()
+	l.pos++
+	var class bool
+	for int(l.pos) < len(l.input) {
+		c := l.input[l.pos]
+		l.pos++
+		switch {
+		case c == nl || c == cr:
+			return false
+		case c == '\\':
+			if int(l.pos) < len(l.input) && l.input[l.pos] != nl {
+				l.pos++
+			}
+		case c == '[':
+			class = true
+		case c == ']':
+			class = false
+		case c == '/' && !class:
+			for int(l.pos) < len(l.input) && isIdentByte(l.input[l.pos]) {
+				l.pos++
+			}
+			l.emit(tokenQuotedText)
+			return true
+		}
+	}
+	return false
+}
+
+// lexTemplate consumes a template literal, or the rest of one, from the
+// opening ` or the } that closes a substitution, to either the closing ` or
+// the ${ that opens a substitution, and emits it as quoted text. If it
+// stopped at a substitution, open is true. If the literal isn't terminated,
+// ok is false.
+func (l *lexer) lexTemplate() (open, ok bool) {
+	l.emitText()
+	l.pos++
+	for int(l.pos) < len(l.input) {
+		c := l.input[l.pos]
+		l.pos++
+		switch {
+		case c == '\\':
+			if int(l.pos) < len(l.input) {
+				l.pos++
+			}
+		case c == '`':
+			l.emit(tokenQuotedText)
+			return false, true
+		case c == '$' && l.hasPrefix("{"):
+			l.pos++
+			l.emit(tokenQuotedText)
+			return true, true
+		}
+	}
+	return false, false
+}
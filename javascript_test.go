@@ -0,0 +1,63 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestJavaScript(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "// a\nvar a = 1; /* b */ // c\n", "\nvar a = 1;  \n", ""},
+		{"strings", "f(\"// a\", '/* b */'); // c\n", "f(\"// a\", '/* b */'); \n", ""},
+		{"hashbang", "#!/usr/bin/env node\n// a\n", "#!/usr/bin/env node\n\n", ""},
+		{"regexp", "var re = /https?:\\/\\//; // a\n", "var re = /https?:\\/\\//; \n", ""},
+		{"regexpStar", "x = /\\/*foo/g; /* a */", "x = /\\/*foo/g; ", ""},
+		{"regexpClass", "x = /[/]*/.test(s) // a\n", "x = /[/]*/.test(s) \n", ""},
+		{"regexpReturn", "return /a\\/b/ // c\n", "return /a\\/b/ \n", ""},
+		{"regexpArg", "s.replace(/\\/\\//g, '') // a\n", "s.replace(/\\/\\//g, '') \n", ""},
+		{"regexpCondition", "if (x) /a\\/b/.test(s) // c\n", "if (x) /a\\/b/.test(s) \n", ""},
+		{"regexpLoop", "while (f(x)) /[/]/g.exec(s) // c\n", "while (f(x)) /[/]/g.exec(s) \n", ""},
+		{"divisionCall", "x = f(a) / g(b) / 2 // c\n", "x = f(a) / g(b) / 2 \n", ""},
+		{"division", "x = a / b / c; // a\n", "x = a / b / c; \n", ""},
+		{"divisionParen", "x = (a + b) / 2 /* a */;", "x = (a + b) / 2 ;", ""},
+		{"divisionIncrement", "x = a++ / 2 // a\n", "x = a++ / 2 \n", ""},
+		{"divisionNumber", "x = 10 / 2 // a\n", "x = 10 / 2 \n", ""},
+		{"template", "x = `// a ${b /* c */} /* d */`; // e\n", "x = `// a ${b } /* d */`; \n", ""},
+		{"templateNested", "x = `a ${ \"`\" } ${ f(`${ {a: 1}.a }//`) } b` // c\n", "x = `a ${ \"`\" } ${ f(`${ {a: 1}.a }//`) } b` \n", ""},
+		{"templateBraces", "x = `${ function() { return 1 } }//`; // a\n", "x = `${ function() { return 1 } }//`; \n", ""},
+		{"templateEscape", "x = `\\` \\${a}` // a\n", "x = `\\` \\${a}` \n", ""},
+		{"legal", "/*! (c) a */\nvar a;", "\nvar a;", ""},
+		{"sourceMap", "a();\n//# sourceMappingURL=a.js.map\n", "a();\n\n", ""},
+		{"unclosedComment", "a(); /* b", "", "index 5: unclosed block comment"},
+		{"unclosedRegexp", "x = /a\n/", "", "index 4: unterminated regular expression"},
+		{"unclosedTemplate", "x = `${a}", "", "index 8: unterminated template literal"},
+	}
+	testProfile(t, Stripper{Profile: JavaScript}, tests)
+
+	keep := []profileTest{
+		{"legal", "/*! (c) a */\n/* b */var a;", "/*! (c) a */\nvar a;", ""},
+		{"sourceMap", "a(); // b\n//# sourceMappingURL=a.js.map\n", "a(); \n//# sourceMappingURL=a.js.map\n", ""},
+	}
+	testProfile(t, Stripper{Profile: JavaScript, KeepLegalComments: true, KeepDirectiveComments: true}, keep)
+}
+
+func TestTypeScript(t *testing.T) {
+	tests := []profileTest{
+		{"generic", "let a: Array<string> = []; // a\n", "let a: Array<string> = []; \n", ""},
+		{"regexp", "const re: RegExp = /\\/*/; /* a */", "const re: RegExp = /\\/*/; ", ""},
+	}
+	testProfile(t, Stripper{Profile: TypeScript}, tests)
+}
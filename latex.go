@@ -0,0 +1,99 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// LaTeX is the profile for LaTeX and TeX: % line comments; an escaped
+// percent, \%, is not a comment. Like other line comments, a comment ends
+// before the EOL. TeX also ignores the EOL and the leading whitespace of the
+// next line, which matters when the comment directly follows text, e.g. {%:
+// there, eliding it without them would add a space, so the comment includes
+// them. A comment alone on its line includes its EOL, as the blank line left
+// by eliding it would start a paragraph. The content of \verb and of
+// verbatim environments, e.g. verbatim and lstlisting, is left alone.
+var LaTeX = &Profile{
+	Name:       "latex",
+	Aliases:    []string{"tex", "plaintex"},
+	Extensions: []string{".tex", ".ltx", ".sty", ".cls"},
+	Hints:      []string{"\\documentclass", "\\begin{", "\\end{", "\\section", "\\usepackage", "\\item"},
+	lexText:    lexLaTeX,
+}
+
+// latexVerbatim matches the start of a verbatim environment.
+var latexVerbatim = regexp.MustCompile(`^\\begin\{(verbatim\*?|Verbatim\*?|lstlisting|minted|alltt)\}`)
+
+// lexLaTeX lexes LaTeX.
+func lexLaTeX(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '%' && !l.escaped(`\`):
+			if l.atFirstNonSpace() {
+				// the comment's line would be left blank
+				l.emitText()
+				l.pos = l.lineEnd(l.pos)
+				l.skipEOL()
+				l.emit(tokenLineComment)
+				continue
+			}
+			if isSpace(l.input[l.pos-1]) {
+				l.lexLineComment(tokenLineComment)
+				continue
+			}
+			// the comment joins its line to the next one
+			l.emitText()
+			l.pos = l.lineEnd(l.pos)
+			l.skipEOL()
+			for l.hasPrefix(" ") || l.hasPrefix("\t") {
+				l.pos++
+			}
+			l.emit(tokenLineComment)
+		case c == '\\' && l.hasPrefix("\\begin{"):
+			m := latexVerbatim.FindSubmatch(l.input[l.pos:])
+			if m == nil {
+				l.pos++
+				continue
+			}
+			end := []byte("\\end{" + string(m[1]) + "}")
+			i := bytes.Index(l.input[int(l.pos)+len(m[0]):], end)
+			if i < 0 {
+				l.emitText()
+				return l.errorf("unclosed verbatim environment")
+			}
+			l.pos += Pos(len(m[0]) + i + len(end))
+		case c == '\\' && l.hasPrefix("\\verb") && !l.escaped(`\`):
+			// \verb|...| or \verb*|...|: any character delimits it
+			i := l.pos + 5
+			if int(i) < len(l.input) && l.input[i] == '*' {
+				i++
+			}
+			if int(i) >= len(l.input) || isIdentByte(l.input[i]) {
+				l.pos++
+				continue
+			}
+			j := bytes.IndexByte(l.input[i+1:l.lineEnd(i)], l.input[i])
+			if j < 0 {
+				l.pos++
+				continue
+			}
+			l.pos = i + 1 + Pos(j) + 1
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
@@ -0,0 +1,38 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestLaTeX(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "% a\n\\section{A} % b\nText\n", "\\section{A} \nText\n", ""},
+		{"join", "\\newcommand{\\a}{%\n    b}\n", "\\newcommand{\\a}{b}\n", ""},
+		{"indent", "a % b\n  c\n", "a \n  c\n", ""},
+		{"joinCRLF", "a%\r\n  b\r\n", "ab\r\n", ""},
+		{"paragraph", "a\n% b\n\nc\n", "a\n\nc\n", ""},
+		{"alone", "a\n  % b\r\nc\n%", "a\n  c\n", ""},
+		{"escaped", "100\\% sure % a\n", "100\\% sure \n", ""},
+		{"escapedBackslash", "a\\\\% b\nc", "a\\\\c", ""},
+		{"verb", "\\verb|%| and \\verb*+%+ % a\n", "\\verb|%| and \\verb*+%+ \n", ""},
+		{"verbatim", "\\begin{verbatim}\n% not a comment\n\\end{verbatim} % a\n", "\\begin{verbatim}\n% not a comment\n\\end{verbatim} \n", ""},
+		{"lstlisting", "\\begin{lstlisting}\n%\n\\end{lstlisting}", "\\begin{lstlisting}\n%\n\\end{lstlisting}", ""},
+		{"environment", "\\begin{itemize} % a\n\\end{itemize}", "\\begin{itemize} \n\\end{itemize}", ""},
+		{"unclosedVerbatim", "a \\begin{verbatim} %", "", "index 2: unclosed verbatim environment"},
+	}
+	testProfile(t, Stripper{Profile: LaTeX}, tests)
+}
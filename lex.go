@@ -23,8 +23,8 @@ package nocomment
 import (
 	"bytes"
 	"fmt"
-	"strings"
-	"unicode/utf8"
+	"sort"
+	"sync"
 )
 
 // Pos is a byte position in the original input text.
@@ -43,7 +43,7 @@ func (t token) String() string {
 	case t.typ == tokenError:
 		return t.value
 	}
-	return fmt.Sprintf("%s", t.value)
+	return t.value
 }
 
 func (t token) Error() string {
@@ -51,12 +51,8 @@ func (t token) Error() string {
 }
 
 const (
-	cppComment    = "//"
-	shellComment  = "#"
-	cCommentBegin = "/*"
-	cCommentEnd   = "*/"
-	cr            = '\r'
-	nl            = '\n'
+	cr = '\r'
+	nl = '\n'
 )
 
 type tokenType int
@@ -64,98 +60,135 @@ type tokenType int
 const (
 	tokenError tokenType = iota
 	tokenEOF
-	tokenText          // anything that isn't one of the following
-	tokenCPPComment    // //
-	tokenShellComment  // #
-	tokenCCommentStart // /*
-	tokenCCommentEnd   // */
-	tokenCComment      // /* */
-	tokenQuotedText    // text that is quoted
-	tokenDoubleQuote   // "
+	tokenText             // anything that isn't one of the following
+	tokenCPPComment       // //
+	tokenShellComment     // #
+	tokenCComment         // /* */
+	tokenQuotedText       // text that is quoted
+	tokenLineComment      // a line comment that isn't // or #, e.g. SQL's --
+	tokenBlockComment     // a block comment that isn't /* */, e.g. HTML's <!-- -->
+	tokenDirectiveComment // a comment that is an instruction to a tool
+	tokenLegalComment     // a comment that should survive minification: /*! */
+	tokenDocComment       // a comment that is documentation, e.g. Rust's ///
 )
 
-var key = map[string]tokenType{
-	"//": tokenCPPComment,
-	"#":  tokenShellComment,
-	"/*": tokenCCommentStart,
-	"*/": tokenCCommentEnd,
-	"\"": tokenDoubleQuote,
-}
-
-type commentType int
+// CommentType is the type of a comment; see Stripper for which comments are
+// of which type.
+type CommentType int
 
 const (
-	none commentType = iota
+	none CommentType = iota
 	// C++ style comments
 	CPPComment
 	// shell style comments
 	ShellComment
 	// C style comments
 	CComment
-	// quotes aren't comment types but they are here as an unexported value
-	// because we need to handle quoted text (which may have comment delimeters
-	// in them which should not be processed as comments)
-	// feels weird: -mohae
-	doubleQuote // ""
+	// line comments that are neither C++ nor shell style
+	LineComment
+	// block comments that aren't C style
+	BlockComment
+	// comments that are instructions to a tool
+	DirectiveComment
+	// comments that should be preserved
+	LegalComment
+	// documentation comments
+	DocComment
 )
 
-const eof = -1
+var commentTypeNames = [...]string{"none", "C++ comment", "shell comment", "C comment", "line comment", "block comment", "directive comment", "legal comment", "doc comment"}
+
+func (c CommentType) String() string {
+	if c < 0 || int(c) >= len(commentTypeNames) {
+		return fmt.Sprintf("CommentType(%d)", int(c))
+	}
+	return commentTypeNames[c]
+}
+
+// commentType returns the type of comment tokens of type typ are; if they
+// aren't comments, none is returned.
+func commentType(typ tokenType) CommentType {
+	switch typ {
+	case tokenCPPComment:
+		return CPPComment
+	case tokenShellComment:
+		return ShellComment
+	case tokenCComment:
+		return CComment
+	case tokenLineComment:
+		return LineComment
+	case tokenBlockComment:
+		return BlockComment
+	case tokenDirectiveComment:
+		return DirectiveComment
+	case tokenLegalComment:
+		return LegalComment
+	case tokenDocComment:
+		return DocComment
+	}
+	return none
+}
 
 type stateFn func(*lexer) stateFn
 
 type lexer struct {
-	input      []byte     // the string being scanned
-	state      stateFn    // the next lexing function to enter
-	pos        Pos        // current position of this item
-	start      Pos        // start position of this item
-	width      Pos        // width of last rune read from input
-	lastPos    Pos        // position of most recent item returned by nextItem
-	tokens     chan token // channel of scanned tokens
-	parenDepth int        // nesting depth of () exprs <- probably not needed
+	input    []byte     // the string being scanned
+	profile  *Profile   // the profile being used; nil is the default
+	state    stateFn    // the next lexing function to enter
+	pos      Pos        // current position of this item
+	start    Pos        // start position of this item
+	lastPos  Pos        // position of most recent item returned by nextItem
+	tokens   chan token // channel of scanned tokens
+	heredocs []heredoc  // heredocs whose bodies start at the next line
+	// embeds are the regions of embedded code found so far, in order; mu
+	// guards them, as they are read while the lexer runs.
+	mu     sync.Mutex
+	embeds []embedding
+}
+
+// embedding is a region of the input that is code in another language, e.g.
+// JavaScript in HTML.
+type embedding struct {
+	profile *Profile // the rules of the code
+	start   Pos
+	end     Pos    // -1 until the end of the region is known
+	close   string // the text that ends the region, e.g. </script
 }
 
 func lex(input []byte) *lexer {
+	return lexProfile(input, nil)
+}
+
+// lexProfile lexes the input using the rules of the profile. If the profile
+// is nil, the default rules are used.
+func lexProfile(input []byte, p *Profile) *lexer {
+	l := newLexer(input, p)
+	go l.run()
+	return l
+}
+
+// newLexer returns a lexer for the input that hasn't been started.
+func newLexer(input []byte, p *Profile) *lexer {
 	l := lexer{
-		input:  input,
-		state:  lexText,
-		tokens: make(chan token, 2),
+		input:   input,
+		profile: p,
+		state:   lexText,
+		tokens:  make(chan token, 2),
+	}
+	if p != nil && p.lexText != nil {
+		l.state = p.lexText
 	}
-	go l.run()
 	return &l
 }
 
 // run lexes the input by executing state functions until the state is nil.
 func (l *lexer) run() {
-	for state := lexText; state != nil; {
+	for state := l.state; state != nil; {
 		state = state(l)
 	}
 	close(l.tokens) // No more tokens will be delivered
 }
 
-// next returns the next rune in the input.
-func (l *lexer) next() rune {
-	if int(l.pos) >= len(l.input) {
-		l.width = 0
-		return eof
-	}
-	r, w := utf8.DecodeRune(l.input[l.pos:])
-	l.width = Pos(w)
-	l.pos += l.width
-	return r
-}
-
-// peek returns but does not consume the next rune in the input
-func (l *lexer) peek() rune {
-	r := l.next()
-	l.backup()
-	return r
-}
-
-// backup steps back one rune. Can be called only once per call of next.
-func (l *lexer) backup() {
-	l.pos -= l.width
-}
-
 // emit passes an item back to the client.
 func (l *lexer) emit(t tokenType) {
 	l.tokens <- token{t, l.start, string(l.input[l.start:l.pos])}
@@ -167,15 +200,6 @@ func (l *lexer) ignore() {
 	l.start = l.pos
 }
 
-// accept consumes the next rune if it's from the valid set.
-func (l *lexer) accept(valid string) bool {
-	if strings.IndexRune(valid, l.next()) >= 0 {
-		return true
-	}
-	l.backup()
-	return false
-}
-
 // error returns an error token and terminates the scan by passing back a nil
 // pointer that will be the next state, terminating l.run.
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
@@ -196,137 +220,266 @@ func (l *lexer) drain() {
 	}
 }
 
-// stateFn to process input and tokenize things
-func lexText(l *lexer) stateFn {
-	for {
-		is, typ := l.atComment()
-		if is {
-			if l.pos > l.start {
-				l.emit(tokenText)
-			}
-			switch typ {
-			case CPPComment:
-				return lexCPPComment
-			case ShellComment:
-				return lexShellComment
-			case CComment:
-				return lexCComment
-			//case quoteSingle:
-			//return lexSingleQuote
-			case doubleQuote:
-				return lexDoubleQuote
-				//case quoteTick:
-				//return lexQuote
-			}
-		}
-		if l.next() == eof {
-			break
-		}
-	}
-	// Correctly reached EOF.
+// hasPrefix returns whether the unprocessed input starts with s.
+func (l *lexer) hasPrefix(s string) bool {
+	return bytes.HasPrefix(l.input[l.pos:], []byte(s))
+}
+
+// emitText emits any pending text.
+func (l *lexer) emitText() {
 	if l.pos > l.start {
 		l.emit(tokenText)
 	}
-	l.emit(tokenEOF) // Useful to make EOF a token
-	return nil       // Stop the run loop.
-}
-
-// atComment returns if the next rune(s) are either a comment or a quote and if
-// so, its type.
-func (l *lexer) atComment() (is bool, typ commentType) {
-	r, s := utf8.DecodeRune(l.input[l.pos:])
-	// with one character, only ShellComment or quote will match, We check to see
-	// which it was and if it was neither process an additional rune, which is
-	// probably unnecessary.
-	t, ok := key[string(r)]
-	if ok {
-		switch t {
-		case tokenShellComment:
-			return true, ShellComment
-		case tokenDoubleQuote:
-			return true, doubleQuote
+}
+
+// atLineStart returns whether the current position is the start of a line.
+func (l *lexer) atLineStart() bool {
+	return l.pos == 0 || l.input[l.pos-1] == nl || l.input[l.pos-1] == cr
+}
+
+// atFirstNonSpace returns whether the current position is the first
+// character of its line that isn't a space or a tab.
+func (l *lexer) atFirstNonSpace() bool {
+	for i := l.pos - 1; i >= 0; i-- {
+		switch l.input[i] {
+		case ' ', '\t':
+		case nl, cr:
+			return true
+		default:
+			return false
 		}
 	}
-	// otherwise get a second rune
-	rr, _ := utf8.DecodeRune(l.input[int(l.pos)+s:])
-	t, ok = key[string(r)+string(rr)]
-	if !ok {
-		return false, none
+	return true
+}
+
+// escaped returns whether the text at the current position is escaped: it is
+// preceded by an odd number of escapes.
+func (l *lexer) escaped(escape string) bool {
+	n := 0
+	for i := int(l.pos); i >= len(escape) && string(l.input[i-len(escape):i]) == escape; i -= len(escape) {
+		n++
 	}
-	switch t {
-	case tokenCPPComment:
-		return true, CPPComment
-	case tokenCCommentStart:
-		return true, CComment
+	return n%2 == 1
+}
+
+// lineEnd returns the position of the EOL that terminates the line containing
+// pos; for \r\n this is the position of the \r. If there isn't an EOL, the
+// length of the input is returned.
+func (l *lexer) lineEnd(pos Pos) Pos {
+	i := bytes.IndexByte(l.input[pos:], nl)
+	if i < 0 {
+		return Pos(len(l.input))
+	}
+	if i > 0 && l.input[int(pos)+i-1] == cr {
+		i--
 	}
-	return false, none
+	return pos + Pos(i)
 }
 
-// lexCPPComment handles lexing of C++ style comments: // to \n or eof
-func lexCPPComment(l *lexer) stateFn {
-	// scan until the comment is consumed: EOL is encountered
-	for {
-		r := l.next()
-		if r == nl || r == eof {
-			break
+// lexLineComment consumes a line comment that starts at the current position
+// and emits it as typ. Unlike the default C++ and shell comments, the EOL
+// is not part of the comment: the line structure of the input is preserved.
+func (l *lexer) lexLineComment(typ tokenType) {
+	l.emitText()
+	l.pos = l.lineEnd(l.pos)
+	l.emit(typ)
+}
+
+// lexDelimited consumes everything from the current position through the
+// end delimiter, after skipping len(begin) bytes, and emits it as typ. If the
+// end delimiter isn't found, false is returned.
+func (l *lexer) lexDelimited(begin, end string, typ tokenType) bool {
+	l.emitText()
+	i := bytes.Index(l.input[int(l.pos)+len(begin):], []byte(end))
+	if i < 0 {
+		return false
+	}
+	l.pos += Pos(len(begin) + i + len(end))
+	l.emit(typ)
+	return true
+}
+
+// lexNested is lexDelimited for comments that nest: each begin delimiter
+// within the comment must be matched by an end delimiter.
+func (l *lexer) lexNested(begin, end string, typ tokenType) bool {
+	l.emitText()
+	l.pos += Pos(len(begin))
+	for depth := 1; depth > 0; {
+		switch {
+		case int(l.pos) >= len(l.input):
+			return false
+		case l.hasPrefix(end):
+			l.pos += Pos(len(end))
+			depth--
+		case l.hasPrefix(begin):
+			l.pos += Pos(len(begin))
+			depth++
+		default:
+			l.pos++
+		}
+	}
+	l.emit(typ)
+	return true
+}
+
+// lexQuoted consumes a string that starts at the current position with
+// the quote rune and emits it as tokenQuotedText. If escape is not 0, it
+// escapes the rune that follows it. If doubled is true, two consecutive
+// quotes are an escaped quote. If the string isn't terminated, false is
+// returned.
+func (l *lexer) lexQuoted(quote, escape byte, doubled bool) bool {
+	l.emitText()
+	l.pos++
+	for int(l.pos) < len(l.input) {
+		c := l.input[l.pos]
+		l.pos++
+		switch {
+		case escape != 0 && c == escape:
+			if int(l.pos) < len(l.input) {
+				l.pos++
+			}
+		case c == quote:
+			if doubled && int(l.pos) < len(l.input) && l.input[l.pos] == quote {
+				l.pos++
+				continue
+			}
+			l.emit(tokenQuotedText)
+			return true
 		}
 	}
-	// comment is done, ignore processed runes and continue lexing
-	l.emit(tokenCPPComment)
-	return lexText
+	return false
 }
 
-// lexShellComment handles lexing of shell style comments: # to \n or eof
-func lexShellComment(l *lexer) stateFn {
-	// scan until the comment is consumed: EOL is encountered
+// lexEmbedded lexes the input from the current position to end, which is
+// content in another language, using the profile p; close is the text that
+// ends the content. The tokens are passed on as if they were produced by l.
+// If an error occurs, the error token is passed on and false is returned;
+// lexing must stop.
+func (l *lexer) lexEmbedded(p *Profile, end Pos, close string) bool {
+	l.emitText()
+	l.embed(p, end, close)
+	sub := newLexer(l.input[:end], p)
+	sub.pos, sub.start = l.pos, l.pos
+	go sub.run()
 	for {
-		r := l.next()
-		if r == nl || r == eof {
-			break
+		t := sub.nextToken()
+		switch t.typ {
+		case tokenEOF:
+			l.pos, l.start = end, end
+			return true
+		case tokenError:
+			l.tokens <- t
+			return false
 		}
+		l.tokens <- t
 	}
-	// comment is done, ignore processed runes and continue lexing
-	l.emit(tokenShellComment)
-	return lexText
 }
 
-// lexCComment handles the lexing of C style comments: they start with /* and
-// end with */; they may span new lines
-func lexCComment(l *lexer) stateFn {
-	l.pos += Pos(len(cCommentBegin))
-	// find end of comment or error if none
-	i := bytes.Index(l.input[l.pos:], []byte(cCommentEnd))
-	if i < 0 {
-		return l.errorf("unclosed block comment")
+// embed records that the code from the current position to end, or if end
+// is -1 to where endEmbed is called, is in the language of p, and that close
+// ends it. The index of the embedding is returned.
+func (l *lexer) embed(p *Profile, end Pos, close string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.embeds = append(l.embeds, embedding{profile: p, start: l.pos, end: end, close: close})
+	return len(l.embeds) - 1
+}
+
+// endEmbed sets the end of the embedding i to the current position.
+func (l *lexer) endEmbed(i int) {
+	l.mu.Lock()
+	l.embeds[i].end = l.pos
+	l.mu.Unlock()
+}
+
+// embedding returns the region of embedded code that pos is in, if any.
+func (l *lexer) embedding(pos Pos) *embedding {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := sort.Search(len(l.embeds), func(i int) bool { return l.embeds[i].start > pos }) - 1
+	if i < 0 || (l.embeds[i].end >= 0 && pos >= l.embeds[i].end) {
+		return nil
 	}
-	l.pos += Pos(i + len(cCommentEnd))
-	l.emit(tokenCComment)
-	return lexText
-	// comment is done, ignore processed runes and continue lexing
+	e := l.embeds[i]
+	return &e
 }
 
-// lexQuote processes everything within ""
-func lexDoubleQuote(l *lexer) stateFn {
-	// consume the start quote
-	l.next()
-Loop:
+// collect returns the lexer's tokens, up to EOF, or the error that stopped
+// it.
+func (l *lexer) collect() ([]token, error) {
+	var tokens []token
 	for {
-		switch l.next() {
-		case eof:
+		t := l.nextToken()
+		switch t.typ {
+		case tokenEOF:
+			return tokens, nil
+		case tokenError:
+			return nil, t
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+// lexEOF emits any pending text followed by EOF and stops the run loop.
+func lexEOF(l *lexer) stateFn {
+	l.emitText()
+	l.emit(tokenEOF)
+	return nil
+}
+
+// lexText lexes the input using the profile's delimiters or, if it doesn't
+// have any, the default delimiters.
+func lexText(l *lexer) stateFn {
+	m := l.profile.delimiters()
+	for {
+		i, d := m.index(l.input[l.pos:])
+		if d == nil {
+			l.pos = Pos(len(l.input))
+			return lexEOF
+		}
+		l.pos += Pos(i)
+		if !l.canBegin(d) {
+			l.pos++
+			continue
+		}
+		if l.lexDelimiter(d) {
+			continue
+		}
+		if d.kind == quoteDelim {
 			return l.errorf("unterminated quoted string")
-		case '\\':
-			r := l.peek()
-			// There are two things to look for: is the next char another \ or is it a quote?
-			switch r {
-			case '\\':
-				l.next() // it doesn't start an escape so it is consumed
-			case '"':
-				l.next() // it's an escaped quote so it should be consumed as it's not the end of the quoted text.
-			}
-		case '"':
-			break Loop
 		}
+		return l.errorf("unclosed block comment")
+	}
+}
+
+// lineComment returns the profile's line comment delimiter at the current
+// position, if a comment may begin there; see canBegin. If there isn't one,
+// nil is returned.
+func (l *lexer) lineComment() *delimiter {
+	d := l.profile.delimiters().match(l.input[l.pos:])
+	if d == nil || d.kind != lineDelim || !l.canBegin(d) {
+		return nil
 	}
-	l.emit(tokenQuotedText)
-	return lexText
+	return d
+}
+
+// canBegin returns whether d may begin at the current position: a line
+// comment that must be at the start of its line has to be the first thing on
+// it other than whitespace, and a line comment with an escape can't be
+// escaped.
+func (l *lexer) canBegin(d *delimiter) bool {
+	if d.kind != lineDelim {
+		return true
+	}
+	if d.lineStart && !l.atFirstNonSpace() {
+		return false
+	}
+	return d.escape == "" || !l.escaped(d.escape)
+}
+
+// isIdentByte returns whether c can be part of an identifier. Any byte of a
+// multi-byte UTF-8 sequence is accepted.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
 }
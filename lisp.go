@@ -0,0 +1,177 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"strings"
+)
+
+// Lisp family flags.
+const (
+	lispClojure profileFlag = 1 << iota
+	lispScheme
+	lispElisp
+)
+
+// lispDelims are the characters that end an atom.
+const lispDelims = "()[]{}\";"
+
+// Clojure is the profile for Clojure: ; line comments. The form following
+// #_ is discarded, so it is a comment too; #_#_ discards the two forms
+// following it. Strings are delimited by " and use backslash escapes;
+// character literals, \; and \", are not comments or strings.
+var Clojure = &Profile{
+	Name:       "clojure",
+	Extensions: []string{".clj", ".cljs", ".cljc", ".edn"},
+	Hints:      []string{"(defn ", "(ns ", "(let [", "(def ", ":require"},
+	lexText:    lexLisp,
+	flags:      lispClojure,
+}
+
+// Scheme is the profile for Scheme and Common Lisp: ; line comments and #| |#
+// block comments, which nest. The datum following #; is discarded, so it is
+// a comment too. Strings are delimited by " and use backslash escapes;
+// character literals, #\; and #\", are not comments or strings.
+var Scheme = &Profile{
+	Name:         "scheme",
+	Aliases:      []string{"lisp", "racket", "commonlisp"},
+	Extensions:   []string{".scm", ".ss", ".rkt", ".lisp", ".lsp", ".cl"},
+	Interpreters: []string{"guile", "racket", "sbcl"},
+	Hints:        []string{"(define ", "(lambda ", "(let ((", "(if ", "#t", "(defun "},
+	lexText:      lexLisp,
+	flags:        lispScheme,
+}
+
+// EmacsLisp is the profile for Emacs Lisp: ; line comments. Strings are
+// delimited by " and use backslash escapes; character literals, ?; and ?\",
+// are not comments or strings.
+var EmacsLisp = &Profile{
+	Name:       "emacs-lisp",
+	Aliases:    []string{"elisp"},
+	Extensions: []string{".el"},
+	Filenames:  []string{".emacs"},
+	Hints:      []string{"(defun ", "(setq ", "(require '", "(provide '", "(interactive"},
+	lexText:    lexLisp,
+	flags:      lispElisp,
+}
+
+// lexLisp lexes the Lisp family.
+func lexLisp(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == ';':
+			l.lexLineComment(tokenLineComment)
+		case c == '#' && l.profile.has(lispScheme) && l.hasPrefix("#|"):
+			if !l.lexNested("#|", "|#", tokenBlockComment) {
+				return l.errorf("unclosed block comment")
+			}
+		case (c == '#' && l.profile.has(lispClojure) && l.hasPrefix("#_")) ||
+			(c == '#' && l.profile.has(lispScheme) && l.hasPrefix("#;")):
+			l.emitText()
+			l.pos += 2
+			if !l.skipLispForm() {
+				return l.errorf("unterminated discarded form")
+			}
+			l.emit(tokenBlockComment)
+		case c == '"':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		default:
+			l.skipLispChar()
+		}
+	}
+	return lexEOF
+}
+
+// skipLispChar consumes the character at the current position. If it starts
+// a character literal, the whole literal is consumed.
+func (l *lexer) skipLispChar() {
+	switch {
+	case l.profile.has(lispClojure) && l.hasPrefix("\\"):
+		l.pos++
+	case l.profile.has(lispScheme) && l.hasPrefix("#\\"):
+		l.pos += 2
+	case l.profile.has(lispElisp) && l.hasPrefix("?\\"):
+		l.pos += 2
+	case l.profile.has(lispElisp) && l.hasPrefix("?"):
+		l.pos++
+	}
+	if int(l.pos) < len(l.input) {
+		l.pos++
+	}
+}
+
+// skipLispForm consumes the next form, including any whitespace, comments,
+// and prefixes, e.g. ' or #, before it. If the form isn't terminated, false
+// is returned.
+func (l *lexer) skipLispForm() bool {
+	depth := 0
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case isSpace(c) || c == ',':
+			l.pos++
+			continue
+		case c == ';':
+			l.pos = l.lineEnd(l.pos)
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			l.pos++
+			continue
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			l.pos++
+		case c == '"':
+			if !l.skipLispString() {
+				return false
+			}
+		case (c == '#' && l.profile.has(lispClojure) && l.hasPrefix("#_")) ||
+			(c == '#' && l.profile.has(lispScheme) && l.hasPrefix("#;")):
+			// a discarded form doesn't count: the form after it is skipped
+			l.pos += 2
+			if !l.skipLispForm() {
+				return false
+			}
+			continue
+		case strings.IndexByte("'`~@^#", c) >= 0 && !l.hasPrefix("#\\") && !l.hasPrefix("#|"):
+			// a prefix: the form follows
+			l.pos++
+			continue
+		default:
+			// an atom
+			for int(l.pos) < len(l.input) && !isSpace(l.input[l.pos]) && strings.IndexByte(lispDelims, l.input[l.pos]) < 0 {
+				l.skipLispChar()
+			}
+		}
+		if depth <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// skipLispString consumes a string without emitting it.
+func (l *lexer) skipLispString() bool {
+	for l.pos++; int(l.pos) < len(l.input); l.pos++ {
+		switch l.input[l.pos] {
+		case '\\':
+			l.pos++
+		case '"':
+			l.pos++
+			return true
+		}
+	}
+	return false
+}
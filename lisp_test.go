@@ -0,0 +1,55 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestClojure(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", ";; a\n(def a 1) ; b\n", "\n(def a 1) \n", ""},
+		{"discard", "(+ 1 #_(foo \"bar)\" [2]) 3)", "(+ 1  3)", ""},
+		{"discardAtom", "[1 #_ two 3]", "[1  3]", ""},
+		{"discardQuoted", "(a #_'(b) c)", "(a  c)", ""},
+		{"discardTwice", "[1 #_#_ two 3 4]", "[1  4]", ""},
+		{"char", "(str \\; \\\" \"a;b\") ; c\n", "(str \\; \\\" \"a;b\") \n", ""},
+		{"hashPipe", "(a #|b|#)", "(a #|b|#)", ""},
+		{"unclosedString", "(str \"a)", "", "index 5: unterminated quoted string"},
+		{"unclosedDiscard", "(a #_(b c", "", "index 3: unterminated discarded form"},
+	}
+	testProfile(t, Stripper{Profile: Clojure}, tests)
+}
+
+func TestScheme(t *testing.T) {
+	tests := []profileTest{
+		{"line", "; a\n(define a 1) ; b\n", "\n(define a 1) \n", ""},
+		{"block", "#| a #| b |# c |#(define a 1)", "(define a 1)", ""},
+		{"datum", "(+ 1 #;(2 3) 4)", "(+ 1  4)", ""},
+		{"datumTwice", "(a #; #; b c d)", "(a  d)", ""},
+		{"char", "(list #\\; #\\\" #\\|) ; a\n", "(list #\\; #\\\" #\\|) \n", ""},
+		{"unclosedBlock", "#| a #| b |#", "", "index 0: unclosed block comment"},
+	}
+	testProfile(t, Stripper{Profile: Scheme}, tests)
+}
+
+func TestEmacsLisp(t *testing.T) {
+	tests := []profileTest{
+		{"line", ";;; a\n(setq a 1) ; b\n", "\n(setq a 1) \n", ""},
+		{"char", "(list ?; ?\\\" ?\\;) ; a\n", "(list ?; ?\\\" ?\\;) \n", ""},
+		{"string", "(message \"a ; \\\" b\") ; c\n", "(message \"a ; \\\" b\") \n", ""},
+	}
+	testProfile(t, Stripper{Profile: EmacsLisp}, tests)
+}
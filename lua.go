@@ -0,0 +1,99 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// Lua is the profile for Lua: -- line comments and --[[ ]] block comments.
+// Block comments and long strings, [[ ]], use long brackets: any number of =
+// may be between the brackets, [==[ ]==], and only a closing bracket of the
+// same level ends them. Strings are delimited by " or ' and use backslash
+// escapes. A #! line at the start of the input is not a comment.
+var Lua = &Profile{
+	Name:         "lua",
+	Extensions:   []string{".lua"},
+	Interpreters: []string{"lua", "luajit"},
+	Hints:        []string{"local ", "function ", " then", "~=", "elseif ", "require(\"", "end\n"},
+	lexText:      lexLua,
+}
+
+// lexLua lexes Lua.
+func lexLua(l *lexer) stateFn {
+	if l.pos == 0 && l.hasPrefix("#") {
+		l.pos = l.lineEnd(l.pos)
+	}
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '-' && l.hasPrefix("--"):
+			level := l.longBracketLevel(l.pos + 2)
+			if level < 0 {
+				l.lexLineComment(tokenLineComment)
+				continue
+			}
+			if !l.lexLongBracket(2, level, tokenBlockComment) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '[':
+			level := l.longBracketLevel(l.pos)
+			if level < 0 {
+				l.pos++
+				continue
+			}
+			if !l.lexLongBracket(0, level, tokenQuotedText) {
+				return l.errorf("unterminated long string")
+			}
+		case c == '"' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// longBracketLevel returns the level, the number of =, of the opening long
+// bracket at pos. If there isn't one, -1 is returned.
+func (l *lexer) longBracketLevel(pos Pos) int {
+	if int(pos) >= len(l.input) || l.input[pos] != '[' {
+		return -1
+	}
+	i := int(pos) + 1
+	for i < len(l.input) && l.input[i] == '=' {
+		i++
+	}
+	if i >= len(l.input) || l.input[i] != '[' {
+		return -1
+	}
+	return i - int(pos) - 1
+}
+
+// lexLongBracket consumes everything from the current position through the
+// closing long bracket of the given level and emits it as typ. The opening
+// long bracket is n bytes from the current position. If the closing bracket
+// isn't found, false is returned.
+func (l *lexer) lexLongBracket(n, level int, typ tokenType) bool {
+	l.emitText()
+	end := append(append([]byte("]"), bytes.Repeat([]byte("="), level)...), ']')
+	i := bytes.Index(l.input[int(l.pos)+n+level+2:], end)
+	if i < 0 {
+		return false
+	}
+	l.pos += Pos(n + level + 2 + i + len(end))
+	l.emit(typ)
+	return true
+}
@@ -0,0 +1,37 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestLua(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "-- a\nlocal a = 1 -- b\n", "\nlocal a = 1 \n", ""},
+		{"block", "--[[ a\n b ]]local a = 1", "local a = 1", ""},
+		{"blockLevel", "--[==[ a ]] ]=] ]==]local a = 1", "local a = 1", ""},
+		{"notBlock", "--[ a\nlocal a = 1 --[=x\n", "\nlocal a = 1 \n", ""},
+		{"longString", "s = [[ -- a ]] -- b\n", "s = [[ -- a ]] \n", ""},
+		{"longStringLevel", "s = [=[ a ]] -- ]=] -- b\n", "s = [=[ a ]] -- ]=] \n", ""},
+		{"index", "t[1] = a[b[c]] -- a\n", "t[1] = a[b[c]] \n", ""},
+		{"string", "s = \"-- a \\\" --[[\" .. '--' -- b\n", "s = \"-- a \\\" --[[\" .. '--' \n", ""},
+		{"shebang", "#!/usr/bin/lua\n-- a\n", "#!/usr/bin/lua\n\n", ""},
+		{"length", "n = #t -- a\n", "n = #t \n", ""},
+		{"unclosedBlock", "--[==[ a ]]", "", "index 0: unclosed block comment"},
+		{"unclosedLongString", "s = [[ a", "", "index 4: unterminated long string"},
+	}
+	testProfile(t, Stripper{Profile: Lua}, tests)
+}
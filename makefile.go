@@ -0,0 +1,94 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// Makefile is the profile for Makefiles: # comments. An escaped #, \#, is
+// not a comment, and a comment that ends with a backslash continues on the
+// next line. Recipe lines, which start with a tab, are passed to the shell,
+// so they are lexed using the Shell profile. The bodies of define directives
+// are passed through as is.
+var Makefile = &Profile{
+	Name:       "makefile",
+	Aliases:    []string{"make"},
+	Extensions: []string{".mk", ".mak"},
+	Filenames:  []string{"Makefile", "makefile", "GNUmakefile"},
+	Hints:      []string{".PHONY", ":=", "$(", "\t@", "all:", "$@", "$<"},
+	lexText:    lexMakefile,
+	delims:     newMatcher(delimiters{{kind: lineDelim, begin: "#", escape: `\`, typ: tokenShellComment}}),
+}
+
+// lexMakefile lexes Makefiles.
+func lexMakefile(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		if l.atLineStart() {
+			if l.hasPrefix("\t") {
+				if !l.lexEmbedded(Shell, l.splicedLineEnd(l.pos), "\n") {
+					return nil
+				}
+				continue
+			}
+			if l.makeDirective() == "define" {
+				l.skipMakeDefine()
+				continue
+			}
+		}
+		switch c := l.input[l.pos]; {
+		case c == '#' && l.lineComment() != nil:
+			l.emitText()
+			l.pos = l.splicedLineEnd(l.pos)
+			l.emit(tokenShellComment)
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// makeDirective returns the first word of the line at the current position,
+// ignoring any override or export.
+func (l *lexer) makeDirective() string {
+	words := bytes.Fields(l.input[l.pos:l.lineEnd(l.pos)])
+	for len(words) > 1 && (string(words[0]) == "override" || string(words[0]) == "export") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	return string(words[0])
+}
+
+// skipMakeDefine consumes a define directive through its endef. Define
+// directives may nest. The rest of the endef line, e.g. a comment, isn't
+// consumed.
+func (l *lexer) skipMakeDefine() {
+	depth := 0
+	for int(l.pos) < len(l.input) {
+		switch l.makeDirective() {
+		case "define":
+			depth++
+		case "endef":
+			depth--
+			if depth == 0 {
+				l.pos += Pos(bytes.Index(l.input[l.pos:], []byte("endef")) + len("endef"))
+				return
+			}
+		}
+		l.pos = l.lineEnd(l.pos)
+		l.skipEOL()
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestMakefile(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "# a\nCC = gcc # b\n", "\nCC = gcc \n", ""},
+		{"escaped", "HASH = \\# not a comment # a\n", "HASH = \\# not a comment \n", ""},
+		{"escapedBackslash", "A = \\\\# a\n", "A = \\\\\n", ""},
+		{"continued", "# a \\\n  still a\nall:\n", "\nall:\n", ""},
+		{"recipe", "all: # a\n\techo $$# items # b\n\techo '#' \\\n\t  \"#\" # c\n", "all: \n\techo $$# items \n\techo '#' \\\n\t  \"#\" \n", ""},
+		{"define", "define A\n# not a comment\nendef # a\nB = 1\n", "define A\n# not a comment\nendef \nB = 1\n", ""},
+		{"defineNested", "override define A\ndefine B\n#\nendef\n#\nendef\n# a\n", "override define A\ndefine B\n#\nendef\n#\nendef\n\n", ""},
+		{"recipeUnclosedQuote", "all:\n\techo 'a\n", "", "index 11: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Makefile}, tests)
+}
@@ -0,0 +1,112 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// matcher finds delimiters in text. Text that can't start a delimiter is
+// skipped without being examined further; at each byte that can, a trie of
+// the delimiters' begins is walked. As no begin is a prefix of another, the
+// walk ends at the first delimiter found.
+type matcher struct {
+	first [256]bool // the bytes that begin a delimiter
+	only  int       // if all delimiters begin with the same byte, that byte; otherwise -1
+	root  trieNode
+}
+
+// trieNode is a node of a matcher's trie. A node is either a leaf, which has
+// a delimiter, or has children.
+type trieNode struct {
+	next  map[byte]*trieNode
+	delim *delimiter
+}
+
+// defaultMatcher matches the default delimiters.
+var defaultMatcher = newMatcher(defaultDelimiters)
+
+// newMatcher returns a matcher for the delimiters, which must be a valid set.
+func newMatcher(ds delimiters) *matcher {
+	m := &matcher{only: -1}
+	for i := range ds {
+		d := &ds[i]
+		switch {
+		case i == 0:
+			m.only = int(d.begin[0])
+		case m.only != int(d.begin[0]):
+			m.only = -1
+		}
+		m.first[d.begin[0]] = true
+		n := &m.root
+		for j := 0; j < len(d.begin); j++ {
+			if n.next == nil {
+				n.next = make(map[byte]*trieNode)
+			}
+			child, ok := n.next[d.begin[j]]
+			if !ok {
+				child = &trieNode{}
+				n.next[d.begin[j]] = child
+			}
+			n = child
+		}
+		n.delim = d
+	}
+	return m
+}
+
+// index returns the index of the first delimiter in b and the delimiter. If
+// there isn't one, -1 and nil are returned.
+func (m *matcher) index(b []byte) (int, *delimiter) {
+	for i := 0; ; i++ {
+		j := m.skip(b[i:])
+		if j < 0 {
+			return -1, nil
+		}
+		i += j
+		if d := m.match(b[i:]); d != nil {
+			return i, d
+		}
+	}
+}
+
+// skip returns the index of the first byte of b that may begin a delimiter.
+// If there isn't one, -1 is returned.
+func (m *matcher) skip(b []byte) int {
+	if m.only >= 0 {
+		return bytes.IndexByte(b, byte(m.only))
+	}
+	for i, c := range b {
+		if m.first[c] {
+			return i
+		}
+	}
+	return -1
+}
+
+// match returns the delimiter that begins b. If there isn't one, nil is
+// returned.
+func (m *matcher) match(b []byte) *delimiter {
+	n := &m.root
+	for _, c := range b {
+		n = n.next[c]
+		if n == nil {
+			return nil
+		}
+		if n.delim != nil {
+			return n.delim
+		}
+	}
+	return nil
+}
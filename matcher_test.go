@@ -0,0 +1,127 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	var ds delimiters
+	var err error
+	for _, d := range []delimiter{
+		{kind: lineDelim, begin: "REM "},
+		{kind: blockDelim, begin: "<!--", end: "-->"},
+		{kind: blockDelim, begin: "«", end: "»"},
+		{kind: lineDelim, begin: "<#"},
+	} {
+		ds, err = ds.add(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := newMatcher(ds)
+	tests := []struct {
+		input string
+		index int
+		begin string
+	}{
+		{"", -1, ""},
+		{"plain text", -1, ""},
+		{"REM", -1, ""},
+		{"a REM b", 2, "REM "},
+		{"<p><!- <!-- a", 7, "<!--"},
+		{"a <# b", 2, "<#"},
+		{"x < y « z »", 6, "«"},
+		{"a ½ b", -1, ""},
+	}
+	for _, test := range tests {
+		i, d := m.index([]byte(test.input))
+		if i != test.index {
+			t.Errorf("%q: got index %d want %d", test.input, i, test.index)
+			continue
+		}
+		if d == nil {
+			if test.begin != "" {
+				t.Errorf("%q: got no delimiter want %q", test.input, test.begin)
+			}
+			continue
+		}
+		if d.begin != test.begin {
+			t.Errorf("%q: got %q want %q", test.input, d.begin, test.begin)
+		}
+	}
+
+	// all delimiters begin with the same byte
+	m = newMatcher(delimiters{{kind: lineDelim, begin: "--"}, {kind: blockDelim, begin: "-{", end: "}-"}})
+	if i, d := m.index([]byte("a - b -{ c")); i != 6 || d == nil || d.begin != "-{" {
+		t.Errorf("got %d %v want 6 \"-{\"", i, d)
+	}
+}
+
+// benchInput returns about n bytes of C like code with a comment every few
+// lines.
+func benchInput(n int) []byte {
+	var buf bytes.Buffer
+	for buf.Len() < n {
+		buf.WriteString("int main(int argc, char **argv) {\n")
+		buf.WriteString("\tprintf(\"hello, /* world */ %d\\n\", argc); // greet\n")
+		buf.WriteString("\tfor (int i = 0; i < argc; i++) {\n")
+		buf.WriteString("\t\tputs(argv[i]);\n")
+		buf.WriteString("\t}\n")
+		buf.WriteString("\t/* the exit code\n\t   is always zero */\n")
+		buf.WriteString("\treturn 0; # not really C\n")
+		buf.WriteString("}\n\n")
+	}
+	return buf.Bytes()
+}
+
+func benchmarkClean(b *testing.B, s *Stripper, input []byte) {
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Clean(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCleanDefault(b *testing.B) {
+	var s Stripper
+	benchmarkClean(b, &s, benchInput(1<<20))
+}
+
+func BenchmarkCleanPlainText(b *testing.B) {
+	var s Stripper
+	benchmarkClean(b, &s, bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog.\n"), 1<<20/45))
+}
+
+func BenchmarkCleanCustom(b *testing.B) {
+	var s Stripper
+	for _, d := range []string{"//", "#", "--", ";;", "REM "} {
+		if err := s.AddLineComment(d); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for _, d := range [][2]string{{"/*", "*/"}, {"(*", "*)"}, {"<!--", "-->"}, {"{-", "-}"}} {
+		if err := s.AddBlockComment(d[0], d[1]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := s.AddQuote("\"", "\\"); err != nil {
+		b.Fatal(err)
+	}
+	benchmarkClean(b, &s, benchInput(1<<20))
+}
@@ -19,47 +19,187 @@
 // Block comments start with /* and end with */ and can span lines.
 //
 // Anything within quotes, "", is ignored.
+//
+// Other delimiters can be used by adding them to a Stripper, e.g. ; line
+// comments or (* *) block comments; once any are added, the defaults are no
+// longer used.
+//
+// Languages whose rules differ from the defaults are supported by profiles,
+// e.g. Shell. When a profile is used, line comments end before the EOL so
+// that the structure of the input is preserved.
 package nocomment
 
 // Stripper handles the elision of comments from text. The style of comments to
 // elide is configurable: all supported styles are elided by default.
 type Stripper struct {
+	// Profile: the comment and quoting rules of the input's language. If nil,
+	// the default rules are used.
+	Profile *Profile
 	// KeepCComments: do not elide C style comments (/* */).
 	KeepCComments bool
 	// KeepCPPComments: do not elide C++ style comments (//).
 	KeepCPPComments bool
 	// KeepShellComments: do not elide C style comments (#).
 	KeepShellComments bool
+	// KeepLineComments: do not elide line comments that are neither C++ nor
+	// shell style, e.g. SQL's --. Only profiles and added delimiters produce
+	// these.
+	KeepLineComments bool
+	// KeepBlockComments: do not elide block comments that aren't C style,
+	// e.g. HTML's <!-- -->. Only profiles and added delimiters produce these.
+	KeepBlockComments bool
+	// KeepDirectiveComments: do not elide comments that are instructions to a
+	// tool, e.g. HTML conditional comments. Only profiles produce these.
+	KeepDirectiveComments bool
+	// KeepLegalComments: do not elide comments that are marked as needing to
+	// be preserved, e.g. JavaScript's /*! */. Only profiles produce these.
+	KeepLegalComments bool
+	// KeepDocComments: do not elide documentation comments, e.g. Rust's ///.
+	// Only profiles produce these.
+	KeepDocComments bool
+	// TrimTrailingSpace: remove the trailing whitespace of the lines that
+	// comments are elided from, e.g. the space before a trailing // a.
+	TrimTrailingSpace bool
+	// DropEmptyLines: remove the lines that only contained comments, and
+	// whitespace, once the comments are elided. Lines that were blank to
+	// begin with are kept.
+	DropEmptyLines bool
+	// MaxBlankLines: if greater than 0, runs of blank lines are collapsed to
+	// at most this many lines.
+	MaxBlankLines int
+	// Replace: if set, it is called for each comment that would be elided and
+	// the comment is replaced with what it returns, e.g. /* redacted */. If
+	// it returns nothing, the comment is elided. The replacement must be
+	// nothing but comments and whitespace, by the same rules as the input,
+	// and can't run into the text that follows the comment; otherwise Clean
+	// returns an error. If the comment included its EOL, so does the
+	// replacement. Kept comments aren't passed to Replace.
+	Replace func(c Comment) []byte
+	// delims are the added delimiters.
+	delims delimiters
+	// custom is the profile that uses the added delimiters; it's built when
+	// a delimiter is added so that the delimiters are only compiled once.
+	custom *Profile
+}
+
+// AddLineComment adds a line comment delimiter: a line comment starts with
+// begin and ends with the EOL. // and # comments are C++ and shell style
+// comments; others are elided unless KeepLineComments is set.
+//
+// Once a delimiter has been added, only added delimiters are used. They are
+// ignored if Profile is set. An error is returned if begin is the same as, or
+// a prefix of, another added delimiter, or the other way around.
+func (s *Stripper) AddLineComment(begin string) error {
+	return s.addDelimiter(delimiter{kind: lineDelim, begin: begin, typ: lineCommentType(begin)})
+}
+
+// AddBlockComment adds a block comment delimiter pair, e.g. (* and *). /* */
+// comments are C style comments; others are elided unless KeepBlockComments
+// is set. See AddLineComment for how delimiters are validated.
+func (s *Stripper) AddBlockComment(begin, end string) error {
+	return s.addDelimiter(delimiter{kind: blockDelim, begin: begin, end: end, typ: blockCommentType(begin, end)})
+}
+
+// AddQuote adds a quote delimiter: quoted text starts and ends with quote
+// and is never scanned for comments. If escape isn't empty, it escapes the
+// character that follows it, e.g. \. If escape is quote, a doubled quote is
+// an escaped quote, as in SQL. See AddLineComment for how delimiters are
+// validated.
+func (s *Stripper) AddQuote(quote, escape string) error {
+	return s.addDelimiter(delimiter{kind: quoteDelim, begin: quote, end: quote, escape: escape, typ: tokenQuotedText})
+}
+
+// addDelimiter validates d and adds it to the Stripper's delimiters.
+func (s *Stripper) addDelimiter(d delimiter) error {
+	ds, err := s.delims.add(d)
+	if err != nil {
+		return err
+	}
+	s.delims = ds
+	s.custom = &Profile{Name: "custom", lexText: lexText, delims: newMatcher(ds)}
+	return nil
+}
+
+// profile returns the profile to lex the input with: Profile or, if it isn't
+// set, a profile using the added delimiters, if any.
+func (s *Stripper) profile() *Profile {
+	if s.Profile != nil || s.custom == nil {
+		return s.Profile
+	}
+	return s.custom
+}
+
+// CleanFile removes comments from the input, which is the content of the
+// named file. If Profile isn't set, the profile is detected from the file's
+// name and content, see DetectProfile; if none is found, the default rules
+// are used.
+func (s *Stripper) CleanFile(filename string, input []byte) ([]byte, error) {
+	if s.Profile != nil {
+		return s.Clean(input)
+	}
+	c := *s
+	c.Profile = DetectProfile(filename, input)
+	return c.Clean(input)
 }
 
 // Clean removes comments from the input.
 func (s *Stripper) Clean(input []byte) (b []byte, err error) {
 	// make output the same cap as input
 	b = make([]byte, 0, len(input))
-	l := lex(input)
+	// removed are the output positions of the lines that comments were
+	// removed from; they are only needed to tidy those lines.
+	var removed []int
+	track := s.TrimTrailingSpace || s.DropEmptyLines
+	l := lexProfile(input, s.profile())
+	// an error from Replace stops Clean before the lexer is done
+	defer l.drain()
 	for {
 		t := l.nextToken()
 		switch t.typ {
-		case tokenCComment:
-			if !s.KeepCComments { // if C comments are to be elided, don't append this token
-				continue
-			}
-		case tokenCPPComment:
-			if !s.KeepCPPComments { // if C++ comments are to be elided, don't append this token
-				continue
-			}
-		case tokenShellComment:
-			if !s.KeepShellComments { // if shell comments are to be elided, don't append this token
-				continue
-			}
 		case tokenEOF:
-			goto done
+			return s.tidy(b, removed), nil
 		case tokenError:
 			return b, t
 		}
+		if !s.keep(t.typ) {
+			if s.Replace != nil {
+				r, err := s.replace(input, t, l.embedding(t.pos))
+				if err != nil {
+					return b, err
+				}
+				if len(r) > 0 {
+					b = append(b, r...)
+					continue
+				}
+			}
+			if track {
+				removed, b = appendRemoved(removed, b, t.value)
+			}
+			continue
+		}
 		b = append(b, t.String()...)
 	}
+}
 
-done:
-	return b, nil
+// keep returns whether tokens of type typ are kept.
+func (s *Stripper) keep(typ tokenType) bool {
+	switch typ {
+	case tokenCComment:
+		return s.KeepCComments
+	case tokenCPPComment:
+		return s.KeepCPPComments
+	case tokenShellComment:
+		return s.KeepShellComments
+	case tokenLineComment:
+		return s.KeepLineComments
+	case tokenBlockComment:
+		return s.KeepBlockComments
+	case tokenDirectiveComment:
+		return s.KeepDirectiveComments
+	case tokenLegalComment:
+		return s.KeepLegalComments
+	case tokenDocComment:
+		return s.KeepDocComments
+	}
+	return true
 }
@@ -152,3 +152,30 @@ func TestUnterminatedQuotedStringBug(t *testing.T) {
 		}
 	}
 }
+
+type profileTest struct {
+	name   string
+	input  string
+	output string
+	err    string
+}
+
+// testProfile runs the tests through s.
+func testProfile(t *testing.T, s Stripper, tests []profileTest) {
+	for _, test := range tests {
+		result, err := s.Clean([]byte(test.input))
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; wanted %q", test.name, test.err)
+			continue
+		}
+		if string(result) != test.output {
+			t.Errorf("%s: got %q want %q", test.name, string(result), test.output)
+		}
+	}
+}
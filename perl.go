@@ -0,0 +1,220 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// Perl is the profile for Perl: # line comments. POD, which starts with a
+// line like =pod or =head1 and ends with a =cut line, is a doc comment.
+// Strings, regular expressions, and quote-like operators, e.g. q{}, qw//, and
+// s{}{}, are not scanned for comments; bracket delimiters nest. $# is not a
+// comment. Heredoc bodies and anything after __END__ or __DATA__ are passed
+// through as is.
+var Perl = &Profile{
+	Name:         "perl",
+	Extensions:   []string{".pl", ".pm", ".t"},
+	Interpreters: []string{"perl"},
+	Hints:        []string{"my $", "use strict", "sub ", "=~", "print ", "@ARGV", "$_"},
+	lexText:      lexPerl,
+}
+
+// perlKeywords are the keywords and functions that may be followed by a
+// regular expression.
+var perlKeywords = map[string]bool{
+	"and":    true,
+	"grep":   true,
+	"if":     true,
+	"not":    true,
+	"or":     true,
+	"return": true,
+	"split":  true,
+	"unless": true,
+	"until":  true,
+	"when":   true,
+	"while":  true,
+}
+
+// perlQuoteOps are the quote-like operators and the number of delimited
+// parts each has.
+var perlQuoteOps = map[string]int{
+	"m":  1,
+	"q":  1,
+	"qq": 1,
+	"qr": 1,
+	"qw": 1,
+	"qx": 1,
+	"s":  2,
+	"tr": 2,
+	"y":  2,
+}
+
+// lexPerl lexes Perl.
+func lexPerl(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '=' && l.atLineStart() && l.atPODStart():
+			if l.atLineWord("=cut") {
+				// a =cut without POD to end
+				l.lexLineComment(tokenDocComment)
+				continue
+			}
+			if !l.lexLineBlock("=cut", tokenDocComment) {
+				// POD runs to EOF if it isn't closed
+				l.pos = Pos(len(l.input))
+				l.emit(tokenDocComment)
+			}
+		case c == '_' && l.atLineStart() && (l.atLineWord("__END__") || l.atLineWord("__DATA__")):
+			l.pos = Pos(len(l.input))
+		case c == '#':
+			l.lexLineComment(tokenShellComment)
+		case c == '"' || c == '`' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '/' && l.regexpAllowed(perlKeywords):
+			l.emitText()
+			if !l.skipDelimited(c, false) {
+				return l.errorf("unterminated regular expression")
+			}
+			l.skipIdent()
+			l.emit(tokenQuotedText)
+		case c == '$' && l.hasPrefix("$#"):
+			// $#array or $#{expr}
+			l.pos += 2
+		case c == '<' && l.hasPrefix("<<"):
+			l.perlHeredoc()
+		case c == nl:
+			l.pos++
+			if len(l.heredocs) > 0 {
+				l.lexHeredocs()
+			}
+		case isIdentByte(c):
+			i := l.pos
+			l.skipIdent()
+			parts, ok := perlQuoteOps[string(l.input[i:l.pos])]
+			if !ok || !l.atPerlQuoteOp(i) {
+				continue
+			}
+			end := l.pos
+			l.pos = i
+			l.emitText()
+			l.pos = end
+			if !l.skipPerlQuoteOp(parts) {
+				return l.errorf("unterminated quoted string")
+			}
+			l.emit(tokenQuotedText)
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atPODStart returns whether the line at the current position starts POD: a
+// = followed by an identifier.
+func (l *lexer) atPODStart() bool {
+	i := int(l.pos) + 1
+	return i < len(l.input) && ((l.input[i] >= 'a' && l.input[i] <= 'z') || (l.input[i] >= 'A' && l.input[i] <= 'Z'))
+}
+
+// atPerlQuoteOp returns whether the quote-like operator that starts at i,
+// and ends at the current position, is one: it isn't a method, a variable,
+// a sub name, or a hash key, and a delimiter follows it.
+func (l *lexer) atPerlQuoteOp(i Pos) bool {
+	if i > 0 && (isIdentByte(l.input[i-1]) || l.input[i-1] == '>' || l.input[i-1] == ':' ||
+		l.input[i-1] == '$' || l.input[i-1] == '@' || l.input[i-1] == '%' || l.input[i-1] == '&') {
+		return false
+	}
+	h := int(i)
+	for h > 0 && isSpace(l.input[h-1]) {
+		h--
+	}
+	if bytes.HasSuffix(l.input[:h], []byte("->")) ||
+		(bytes.HasSuffix(l.input[:h], []byte("sub")) && h < int(i) && (h == 3 || !isIdentByte(l.input[h-4]))) {
+		// a method, or the name of a sub
+		return false
+	}
+	j := int(l.pos)
+	for j < len(l.input) && isSpace(l.input[j]) {
+		j++
+	}
+	if j >= len(l.input) {
+		return false
+	}
+	switch c := l.input[j]; {
+	case isIdentByte(c), c == ',', c == ';', c == ')':
+		return false
+	case c == '}' && h > 0 && l.input[h-1] == '{':
+		// a hash key, e.g. $h{y}
+		return false
+	case c == '=' && j+1 < len(l.input) && l.input[j+1] == '>':
+		return false
+	case c == '#' && j > int(l.pos):
+		// a comment, not a delimiter
+		return false
+	}
+	return true
+}
+
+// skipPerlQuoteOp consumes the delimited parts of a quote-like operator and
+// any modifiers. With bracket delimiters, each part has its own pair, which
+// may be separated by whitespace; otherwise the parts share delimiters.
+func (l *lexer) skipPerlQuoteOp(parts int) bool {
+	for isSpace(l.input[l.pos]) {
+		l.pos++
+	}
+	open := l.input[l.pos]
+	for n := 0; n < parts; n++ {
+		if n > 0 {
+			if closingDelim(open) == open {
+				// the closing delimiter of the previous part opens this one
+				l.pos--
+			} else {
+				for int(l.pos) < len(l.input) && isSpace(l.input[l.pos]) {
+					l.pos++
+				}
+				if int(l.pos) >= len(l.input) {
+					return false
+				}
+				open = l.input[l.pos]
+			}
+		}
+		if !l.skipDelimited(open, false) {
+			return false
+		}
+	}
+	l.skipIdent()
+	return true
+}
+
+// perlHeredoc processes a heredoc operator: <<"ID", <<'ID', <<ID, or the
+// indented form, <<~ID. The body is consumed once the rest of the line has
+// been lexed. If the << isn't a heredoc operator, it is consumed as is.
+func (l *lexer) perlHeredoc() {
+	i := int(l.pos) + 2
+	var indent string
+	if i < len(l.input) && l.input[i] == '~' {
+		indent = " \t"
+		i++
+	}
+	delim, n := heredocIdent(l.input[i:])
+	if delim == "" {
+		l.pos += 2
+		return
+	}
+	l.pos = Pos(i + n)
+	l.addHeredoc(delim, indent)
+}
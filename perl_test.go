@@ -0,0 +1,49 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestPerl(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "# a\nprint 1; # b\n", "\nprint 1; \n", ""},
+		{"pod", "=pod\n\n# a\n\n=cut\nprint 1;\n", "\nprint 1;\n", ""},
+		{"podHead", "=head1 NAME\n\na\n\n=cut\n", "\n", ""},
+		{"podCut", "=cut\nprint 1;\n", "\nprint 1;\n", ""},
+		{"podUnclosed", "print 1;\n=head1 A\n", "print 1;\n", ""},
+		{"lastIndex", "my $n = $#a + $#{$b}; # c\n", "my $n = $#a + $#{$b}; \n", ""},
+		{"strings", "print \"# \\\" a\", '# b'; # c\n", "print \"# \\\" a\", '# b'; \n", ""},
+		{"q", "my @a = qw(a # b (c)); # d\n", "my @a = qw(a # b (c)); \n", ""},
+		{"qHash", "my $s = q#a#; # b\n", "my $s = q#a#; \n", ""},
+		{"qComment", "my %h = (q => 1); # a\n", "my %h = (q => 1); \n", ""},
+		{"substitute", "$s =~ s/#/x/g; # a\n", "$s =~ s/#/x/g; \n", ""},
+		{"substituteBrackets", "$s =~ s{#}\n  {x}gx; # a\n", "$s =~ s{#}\n  {x}gx; \n", ""},
+		{"transliterate", "$s =~ tr/#/;/; # a\n", "$s =~ tr/#/;/; \n", ""},
+		{"regexp", "if ($s =~ /#/) { } # a\nsplit /#/, $s; # b\n", "if ($s =~ /#/) { } \nsplit /#/, $s; \n", ""},
+		{"division", "$x = $a / $b; # c / d\n", "$x = $a / $b; \n", ""},
+		{"method", "$o->s(1); # a\n", "$o->s(1); \n", ""},
+		{"methodSpace", "$o-> y(1); # a\n", "$o-> y(1); \n", ""},
+		{"sub", "sub y { 1 } # a\nsub\ts{ 2 } # b\n", "sub y { 1 } \nsub\ts{ 2 } \n", ""},
+		{"hashKey", "$h{y} = $h{ s }; # a\nmap { s/#//r } @a; # b\n", "$h{y} = $h{ s }; \nmap { s/#//r } @a; \n", ""},
+		{"heredoc", "print <<\"EOF\"; # a\n# b\nEOF\n# c\n", "print <<\"EOF\"; \n# b\nEOF\n\n", ""},
+		{"heredocIndented", "print <<~EOF;\n  # b\n  EOF\n", "print <<~EOF;\n  # b\n  EOF\n", ""},
+		{"data", "1; # a\n__DATA__\n# b\n", "1; \n__DATA__\n# b\n", ""},
+		{"unclosedString", "print \"a", "", "index 6: unterminated quoted string"},
+		{"unclosedQuoteOp", "qw(a", "", "index 0: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Perl}, tests)
+}
@@ -0,0 +1,196 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// PHP is the profile for PHP: //, #, and /* */ comments in PHP code, which
+// starts with <?php, <?=, or <? and ends with ?> or EOF. Line comments end at
+// ?> as well as at the EOL; #[ starts an attribute, not a comment. Strings,
+// heredoc and nowdoc bodies, and anything after __halt_compiler are not
+// scanned for comments.
+//
+// Everything outside of PHP code is lexed using the HTML profile; PHP code
+// may also be in a quoted attribute value. HTML comments that contain PHP
+// code are left alone, as the code is still run, as is the content of
+// elements, e.g. script, that contains PHP code.
+//
+// DocBlocks, /** */, are kept if Stripper.KeepDocComments is set.
+var PHP = &Profile{
+	Name:         "php",
+	Extensions:   []string{".php", ".phtml"},
+	Interpreters: []string{"php"},
+	Hints:        []string{"<?php", "$this->", "echo ", "function ", "namespace ", "?>"},
+	lexText:      lexMarkup,
+	flags:        markupPHP,
+}
+
+// lexPHP lexes PHP code; the current position is its open tag. Once its close
+// tag has been consumed, lexing continues with lexMarkup.
+func lexPHP(l *lexer) stateFn {
+	if err := l.lexPHPCode(); err != nil {
+		return l.errorf("%s", err)
+	}
+	return lexMarkup
+}
+
+// phpCode is the profile for PHP code without its open tag, which is how
+// comments in PHP code are checked.
+var phpCode = &Profile{Name: "php code", lexText: lexPHPStatements}
+
+// lexPHPStatements lexes the input as PHP code.
+func lexPHPStatements(l *lexer) stateFn {
+	if err := l.lexPHPStatements(); err != nil {
+		return l.errorf("%s", err)
+	}
+	return lexEOF
+}
+
+// lexPHPCode lexes PHP code from its open tag, at the current position,
+// through its close tag or EOF.
+func (l *lexer) lexPHPCode() error {
+	l.pos += 2 // the rest of the open tag can't be mistaken for anything
+	defer l.endEmbed(l.embed(phpCode, -1, "?>"))
+	return l.lexPHPStatements()
+}
+
+// lexPHPStatements lexes PHP code through its close tag or EOF.
+func (l *lexer) lexPHPStatements() error {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '?' && l.hasPrefix("?>"):
+			l.pos += 2
+			return nil
+		case c == '/' && l.hasPrefix("//"):
+			l.lexPHPLineComment(tokenCPPComment)
+		case c == '#' && !l.hasPrefix("#["):
+			l.lexPHPLineComment(tokenShellComment)
+		case c == '/' && l.hasPrefix("/*"):
+			typ := tokenCComment
+			if l.hasPrefix("/**") && !l.hasPrefix("/**/") {
+				typ = tokenDocComment
+			}
+			if !l.lexDelimited("/*", "*/", typ) {
+				return errors.New("unclosed block comment")
+			}
+		case c == '"' || c == '\'' || c == '`':
+			if !l.lexQuoted(c, '\\', false) {
+				return errors.New("unterminated quoted string")
+			}
+		case c == '<' && l.hasPrefix("<<<"):
+			if !l.lexPHPHeredoc() {
+				return errors.New("unterminated heredoc")
+			}
+		case isIdentByte(c):
+			i := l.pos
+			l.skipIdent()
+			if strings.EqualFold(string(l.input[i:l.pos]), "__halt_compiler") {
+				l.pos = Pos(len(l.input))
+			}
+		default:
+			l.pos++
+		}
+	}
+	return nil
+}
+
+// lexPHPLineComment consumes a line comment, which ends at a close tag as
+// well as at the EOL, and emits it as typ.
+func (l *lexer) lexPHPLineComment(typ tokenType) {
+	l.emitText()
+	end := l.lineEnd(l.pos)
+	if i := bytes.Index(l.input[l.pos:end], []byte("?>")); i >= 0 {
+		end = l.pos + Pos(i)
+	}
+	l.pos = end
+	l.emit(typ)
+}
+
+// lexPHPHeredoc consumes a heredoc or nowdoc, <<<ID, <<<"ID", or <<<'ID',
+// through the identifier that closes it and emits it as quoted text. The
+// closing identifier may be indented and may be followed by more code on its
+// line. If the operator isn't followed by an identifier and the EOL, it is
+// consumed as is. If the closing identifier isn't found, false is returned.
+func (l *lexer) lexPHPHeredoc() bool {
+	i := int(l.pos) + 3
+	for i < len(l.input) && (l.input[i] == ' ' || l.input[i] == '\t') {
+		i++
+	}
+	delim, n := heredocIdent(l.input[i:])
+	i += n
+	if delim == "" || (i < len(l.input) && l.input[i] != nl && l.input[i] != cr) {
+		l.pos += 3
+		return true
+	}
+	l.emitText()
+	l.pos = Pos(i)
+	for {
+		l.pos = l.lineEnd(l.pos)
+		l.skipEOL()
+		if int(l.pos) >= len(l.input) {
+			return false
+		}
+		j := int(l.pos)
+		for j < len(l.input) && (l.input[j] == ' ' || l.input[j] == '\t') {
+			j++
+		}
+		k := j + len(delim)
+		if bytes.HasPrefix(l.input[j:], []byte(delim)) && (k == len(l.input) || !isIdentByte(l.input[k])) {
+			l.pos = Pos(k)
+			l.emit(tokenQuotedText)
+			return true
+		}
+	}
+}
+
+// commentHasPHP returns whether the HTML comment at the current position
+// contains PHP code.
+func (l *lexer) commentHasPHP() bool {
+	end := len(l.input)
+	if i := bytes.Index(l.input[l.pos:], []byte("-->")); i >= 0 {
+		end = int(l.pos) + i
+	}
+	return containsPHP(l.input[l.pos:end])
+}
+
+// containsPHP returns whether b contains a PHP open tag.
+func containsPHP(b []byte) bool {
+	for {
+		i := bytes.Index(b, []byte("<?"))
+		if i < 0 {
+			return false
+		}
+		if isPHPOpenTag(b[i:]) {
+			return true
+		}
+		b = b[i+2:]
+	}
+}
+
+// isPHPOpenTag returns whether b starts with a PHP open tag: <?php, <?=, or
+// <? followed by whitespace. Other processing instructions, e.g. <?xml, aren't
+// PHP code.
+func isPHPOpenTag(b []byte) bool {
+	switch {
+	case len(b) < 3 || b[0] != '<' || b[1] != '?':
+		return false
+	case b[2] == '=' || isSpace(b[2]):
+		return true
+	}
+	return len(b) >= 5 && bytes.EqualFold(b[2:5], []byte("php")) && (len(b) == 5 || isSpace(b[5]))
+}
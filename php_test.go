@@ -0,0 +1,53 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestPHP(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "<?php\n$a = 1; // a\n# b\n/* c */$b = 2;\n", "<?php\n$a = 1; \n\n$b = 2;\n", ""},
+		{"closeTag", "<?php echo 1; // a ?>\n<p>x</p>", "<?php echo 1; ?>\n<p>x</p>", ""},
+		{"hashCloseTag", "<?= $a # b ?><p>", "<?= $a ?><p>", ""},
+		{"blockCloseTag", "<?php /* ?> */ echo 1; ?>", "<?php  echo 1; ?>", ""},
+		{"attribute", "<?php\n#[Route(\"/a\")]\nfunction a() {} # b\n", "<?php\n#[Route(\"/a\")]\nfunction a() {} \n", ""},
+		{"strings", "<?php $a = \"// a\" . '# b' . \"?>\"; // c\n", "<?php $a = \"// a\" . '# b' . \"?>\"; \n", ""},
+		{"heredoc", "<?php\n$a = <<<EOT\n// a\n# b\nEOT;\n// c\n", "<?php\n$a = <<<EOT\n// a\n# b\nEOT;\n\n", ""},
+		{"nowdoc", "<?php\n$a = <<<'EOT'\n  /* a */\n  EOT . 'b'; // c\n", "<?php\n$a = <<<'EOT'\n  /* a */\n  EOT . 'b'; \n", ""},
+		{"heredocPrefix", "<?php\n$a = <<<\"EOT\"\nEOTX // a\nEOT;\n", "<?php\n$a = <<<\"EOT\"\nEOTX // a\nEOT;\n", ""},
+		{"html", "<!-- a --><p><?php echo 1 ?></p><!-- b -->", "<p><?php echo 1 ?></p>", ""},
+		{"htmlCommentPHP", "<!-- <?php echo 1; // a ?> -->", "<!-- <?php echo 1; ?> -->", ""},
+		{"script", "<script>a(); // b\n</script><script>var c = <?= $c ?>; // d\n</script>", "<script>a(); \n</script><script>var c = <?= $c ?>; // d\n</script>", ""},
+		{"attrValue", "<a href=\"<?php echo \"x\" /* c */ ?>\">y</a><!-- d -->", "<a href=\"<?php echo \"x\"  ?>\">y</a>", ""},
+		{"attrValueLine", "<a title='a <?= $t // c ?> b'><!-- d -->", "<a title='a <?= $t ?> b'>", ""},
+		{"attrValueUnclosed", "<a href=\"<?php /* c", "", "index 15: unclosed block comment"},
+		{"xmlDecl", "<?xml version=\"1.0\"?><!-- a --><?php // b\n", "<?xml version=\"1.0\"?><?php \n", ""},
+		{"upperCase", "<?PHP // a\n", "<?PHP \n", ""},
+		{"haltCompiler", "<?php __halt_compiler(); // a\n# b", "<?php __halt_compiler(); // a\n# b", ""},
+		{"noCloseTag", "<?php\necho 1;\n// a", "<?php\necho 1;\n", ""},
+		{"crlf", "<?php // a\r\necho 1;\r\n", "<?php \r\necho 1;\r\n", ""},
+		{"unclosedComment", "<?php /* a", "", "index 6: unclosed block comment"},
+		{"unclosedString", "<?php echo 'a;", "", "index 11: unterminated quoted string"},
+		{"unclosedHeredoc", "<?php $a = <<<EOT\na\n", "", "index 11: unterminated heredoc"},
+	}
+	testProfile(t, Stripper{Profile: PHP}, tests)
+
+	keep := []profileTest{
+		{"docBlock", "<?php\n/** @var int */\n/* a */$a = 1;", "<?php\n/** @var int */\n$a = 1;", ""},
+	}
+	testProfile(t, Stripper{Profile: PHP, KeepDocComments: true}, keep)
+}
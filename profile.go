@@ -0,0 +1,61 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// Profile defines the comment and quoting rules of a language. The rules of
+// most languages can't be expressed by a set of delimiters, so each profile
+// has its own lexing state.
+type Profile struct {
+	// Name of the language.
+	Name string
+	// Aliases are other names of the language, e.g. editor file types; they
+	// are matched by modelines.
+	Aliases []string
+	// Extensions are the file extensions of the language, e.g. ".sh".
+	Extensions []string
+	// Filenames are the names of files in the language that can't be told
+	// by their extension, e.g. "Makefile".
+	Filenames []string
+	// Interpreters are the commands that run scripts in the language; they
+	// are matched by #! lines.
+	Interpreters []string
+	// Hints are text that is typical of the language, e.g. keywords; they
+	// are used by GuessProfile.
+	Hints []string
+	// lexText is the initial state of the profile's lexer.
+	lexText stateFn
+	// flags are language specific options for the profile's lexer.
+	flags profileFlag
+	// delims matches the delimiters used by lexText; if nil, the defaults
+	// are used.
+	delims *matcher
+}
+
+// profileFlag is a set of language specific options; what each flag means
+// depends on the profile's lexer.
+type profileFlag uint
+
+// has returns whether any of the flags are set.
+func (p *Profile) has(f profileFlag) bool {
+	return p != nil && p.flags&f != 0
+}
+
+// delimiters returns the matcher for the profile's delimiters or, if it
+// doesn't have any, the default delimiters.
+func (p *Profile) delimiters() *matcher {
+	if p == nil || p.delims == nil {
+		return defaultMatcher
+	}
+	return p.delims
+}
@@ -0,0 +1,241 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// lexText flags.
+const (
+	// textProfile: line comments end before the EOL, as they do for other
+	// profiles.
+	textProfile profileFlag = 1 << iota
+)
+
+// profileDef is a profile definition, as read from a profile file.
+type profileDef struct {
+	Name          string            `json:"name"`
+	Aliases       []string          `json:"aliases"`
+	Extensions    []string          `json:"extensions"`
+	Filenames     []string          `json:"filenames"`
+	Interpreters  []string          `json:"interpreters"`
+	Hints         []string          `json:"hints"`
+	LineComments  []lineCommentDef  `json:"line_comments"`
+	BlockComments []blockCommentDef `json:"block_comments"`
+	Quotes        []quoteDef        `json:"quotes"`
+}
+
+// lineCommentDef defines a line comment. If LineStart is set, the comment
+// must be the first thing on its line other than whitespace. A Begin that is
+// preceded by Escape, e.g. \#, doesn't begin a comment.
+type lineCommentDef struct {
+	Begin     string `json:"begin"`
+	LineStart bool   `json:"line_start"`
+	Escape    string `json:"escape"`
+}
+
+// blockCommentDef defines a block comment. If Nested is set, block comments
+// nest.
+type blockCommentDef struct {
+	Begin  string `json:"begin"`
+	End    string `json:"end"`
+	Nested bool   `json:"nested"`
+}
+
+// quoteDef defines quoted text. Escape escapes the character that follows
+// it; if it's the quote, a doubled quote is an escaped quote.
+type quoteDef struct {
+	Quote  string `json:"quote"`
+	Escape string `json:"escape"`
+}
+
+// ProfileError is an error in a profile definition.
+type ProfileError struct {
+	Field string // the offending field, e.g. block_comments[1].end
+	Msg   string
+}
+
+func (e *ProfileError) Error() string {
+	if e.Field == "" {
+		return e.Msg
+	}
+	return e.Field + ": " + e.Msg
+}
+
+// ParseProfile returns the profile defined by data, a JSON profile
+// definition:
+//
+//	{
+//		"name": "pascal",
+//		"aliases": ["delphi"],
+//		"extensions": [".pas"],
+//		"line_comments": [{"begin": "//"}],
+//		"block_comments": [{"begin": "(*", "end": "*)"}, {"begin": "{", "end": "}"}],
+//		"quotes": [{"quote": "'", "escape": "'"}]
+//	}
+//
+// Aliases, extensions, filenames, and interpreters are used by
+// LookupProfile and DetectProfile, and hints by GuessProfile; see Profile. A
+// line comment may set line_start, in which case it must be the first thing
+// on its line other than whitespace, and escape, which makes an escaped
+// begin text; a block comment may set nested. Line comments end before the
+// EOL. Errors in the definition are returned as a *ProfileError.
+func ParseProfile(data []byte) (*Profile, error) {
+	var def profileDef
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, jsonProfileError(err)
+	}
+	return def.profile()
+}
+
+// LoadProfile reads the profile definition in the named file and registers
+// the profile. Files whose extension is .yaml or .yml are YAML, see
+// ParseProfileYAML, .toml files are TOML, see ParseProfileTOML, and other
+// files are JSON, see ParseProfile.
+func LoadProfile(filename string) (*Profile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	parse := ParseProfile
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		parse = ParseProfileYAML
+	case ".toml":
+		parse = ParseProfileTOML
+	}
+	p, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	if err := Register(p); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return p, nil
+}
+
+// parseProfileValue returns the profile defined by v, a profile definition
+// decoded from another format, by way of its JSON encoding.
+func parseProfileValue(v interface{}) (*Profile, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, &ProfileError{Msg: err.Error()}
+	}
+	return ParseProfile(data)
+}
+
+// profile validates the definition and returns its profile.
+func (def *profileDef) profile() (*Profile, error) {
+	if def.Name == "" {
+		return nil, &ProfileError{Field: "name", Msg: "is required"}
+	}
+	for i, ext := range def.Extensions {
+		if !strings.HasPrefix(ext, ".") || len(ext) == 1 {
+			return nil, &ProfileError{Field: fmt.Sprintf("extensions[%d]", i), Msg: fmt.Sprintf("%q isn't a file extension, e.g. .txt", ext)}
+		}
+	}
+	var ds delimiters
+	add := func(field string, d delimiter) error {
+		var err error
+		ds, err = ds.add(d)
+		if err != nil {
+			return &ProfileError{Field: field, Msg: err.Error()}
+		}
+		return nil
+	}
+	for i, c := range def.LineComments {
+		field := fmt.Sprintf("line_comments[%d]", i)
+		if c.Begin == "" {
+			return nil, &ProfileError{Field: field + ".begin", Msg: "is required"}
+		}
+		if err := add(field, delimiter{kind: lineDelim, begin: c.Begin, lineStart: c.LineStart, escape: c.Escape, typ: lineCommentType(c.Begin)}); err != nil {
+			return nil, err
+		}
+	}
+	for i, c := range def.BlockComments {
+		field := fmt.Sprintf("block_comments[%d]", i)
+		if c.Begin == "" {
+			return nil, &ProfileError{Field: field + ".begin", Msg: "is required"}
+		}
+		if c.End == "" {
+			return nil, &ProfileError{Field: field + ".end", Msg: "is required"}
+		}
+		if err := add(field, delimiter{kind: blockDelim, begin: c.Begin, end: c.End, nested: c.Nested, typ: blockCommentType(c.Begin, c.End)}); err != nil {
+			return nil, err
+		}
+	}
+	for i, q := range def.Quotes {
+		field := fmt.Sprintf("quotes[%d]", i)
+		if q.Quote == "" {
+			return nil, &ProfileError{Field: field + ".quote", Msg: "is required"}
+		}
+		if err := add(field, delimiter{kind: quoteDelim, begin: q.Quote, end: q.Quote, escape: q.Escape, typ: tokenQuotedText}); err != nil {
+			return nil, err
+		}
+	}
+	if len(def.LineComments) == 0 && len(def.BlockComments) == 0 {
+		return nil, &ProfileError{Field: "line_comments", Msg: "a profile needs at least one line or block comment"}
+	}
+	return &Profile{
+		Name:         def.Name,
+		Aliases:      def.Aliases,
+		Extensions:   def.Extensions,
+		Filenames:    def.Filenames,
+		Interpreters: def.Interpreters,
+		Hints:        def.Hints,
+		lexText:      lexText,
+		flags:        textProfile,
+		delims:       newMatcher(ds),
+	}, nil
+}
+
+// jsonProfileError returns the error from decoding a profile definition as a
+// *ProfileError, where the field is known.
+func jsonProfileError(err error) error {
+	switch e := err.(type) {
+	case *json.UnmarshalTypeError:
+		return &ProfileError{Field: jsonFieldPath(e.Field), Msg: fmt.Sprintf("must be %s, not %s", e.Type, e.Value)}
+	case *json.SyntaxError:
+		return &ProfileError{Msg: fmt.Sprintf("offset %d: %s", e.Offset, e)}
+	}
+	if s := err.Error(); strings.HasPrefix(s, "json: unknown field ") {
+		return &ProfileError{Field: strings.Trim(strings.TrimPrefix(s, "json: unknown field "), `"`), Msg: "unknown field"}
+	}
+	return &ProfileError{Msg: err.Error()}
+}
+
+// jsonFieldPath returns the path of a field as reported by encoding/json,
+// e.g. quotes.0.escape, in the form used by ProfileError: quotes[0].escape.
+func jsonFieldPath(path string) string {
+	var field string
+	for _, s := range strings.Split(path, ".") {
+		if strings.Trim(s, "0123456789") == "" && field != "" {
+			field += "[" + s + "]"
+			continue
+		}
+		if field != "" {
+			field += "."
+		}
+		field += s
+	}
+	return field
+}
@@ -0,0 +1,225 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const pascalDef = `{
+	"name": "test-pascal",
+	"extensions": [".pas"],
+	"line_comments": [{"begin": "//"}, {"begin": "!", "line_start": true}, {"begin": "%", "escape": "\\"}],
+	"block_comments": [{"begin": "(*", "end": "*)", "nested": true}, {"begin": "{", "end": "}"}],
+	"quotes": [{"quote": "'", "escape": "'"}]
+}`
+
+const pascalYAML = `# the test-pascal profile
+name: test-pascal
+extensions: [.pas]
+line_comments:
+  - begin: //
+  - {begin: "!", line_start: true}
+  - begin: "%"
+    escape: '\'
+block_comments:
+- begin: (*
+  end: '*)'
+  nested: true
+- {begin: "{", end: "}"}
+quotes: [{quote: "'", escape: "'"}]
+`
+
+const pascalTOML = `# the test-pascal profile
+name = "test-pascal"
+extensions = [".pas"]
+block_comments = [
+	{begin = "(*", end = "*)", nested = true},
+	{begin = "{", end = "}"},
+]
+quotes = [{quote = "'", escape = "'"}]
+
+[[line_comments]]
+begin = "//"
+
+[[line_comments]]
+begin = "!"
+line_start = true
+
+[[line_comments]]
+begin = "%"
+escape = '\'
+`
+
+func TestParseProfile(t *testing.T) {
+	defs := []struct {
+		format string
+		parse  func([]byte) (*Profile, error)
+		def    string
+	}{
+		{"JSON", ParseProfile, pascalDef},
+		{"YAML", ParseProfileYAML, pascalYAML},
+		{"TOML", ParseProfileTOML, pascalTOML},
+	}
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "a := 1; // b\r\nc;\n", "a := 1; \r\nc;\n", ""},
+		{"lineStart", "  ! a\nb := c ! d;\n", "  \nb := c ! d;\n", ""},
+		{"escape", "a \\% b % c\n\\\\% d\n", "a \\% b \n\\\\\n", ""},
+		{"nested", "a (* b (* c *) d *)e", "a e", ""},
+		{"braces", "a { b }c", "a c", ""},
+		{"quotes", "s := 'it''s // {';", "s := 'it''s // {';", ""},
+		{"unclosedNested", "a (* b (* c *)", "", "index 2: unclosed block comment"},
+	}
+	for _, d := range defs {
+		p, err := d.parse([]byte(d.def))
+		if err != nil {
+			t.Errorf("%s: %s", d.format, err)
+			continue
+		}
+		if p.Name != "test-pascal" || len(p.Extensions) != 1 || p.Extensions[0] != ".pas" {
+			t.Errorf("%s: got %q %q", d.format, p.Name, p.Extensions)
+		}
+		testProfile(t, Stripper{Profile: p}, tests)
+	}
+}
+
+func TestParseProfileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		def  string
+		err  string
+	}{
+		{"syntax", `{"name": }`, "offset 10: invalid character '}' looking for beginning of value"},
+		{"unknown", `{"name": "a", "comments": []}`, "comments: unknown field"},
+		{"type", `{"name": "a", "line_comments": [{"begin": 1}]}`, "line_comments[0].begin: must be string, not number"},
+		{"name", `{"line_comments": [{"begin": ";"}]}`, "name: is required"},
+		{"extension", `{"name": "a", "extensions": [".a", "b"], "line_comments": [{"begin": ";"}]}`, "extensions[1]: \"b\" isn't a file extension, e.g. .txt"},
+		{"begin", `{"name": "a", "line_comments": [{"line_start": true}]}`, "line_comments[0].begin: is required"},
+		{"end", `{"name": "a", "block_comments": [{"begin": "(*"}]}`, "block_comments[0].end: is required"},
+		{"quote", `{"name": "a", "line_comments": [{"begin": ";"}], "quotes": [{"escape": "\\"}]}`, "quotes[0].quote: is required"},
+		{"conflict", `{"name": "a", "line_comments": [{"begin": "#"}], "quotes": [{"quote": "#"}]}`, "quotes[0]: quote \"#\" conflicts with line comment \"#\""},
+		{"ambiguous", `{"name": "a", "line_comments": [{"begin": "--"}], "block_comments": [{"begin": "--[[", "end": "]]"}]}`, "block_comments[0]: block comment \"--[[\" is ambiguous with line comment \"--\""},
+		{"noComments", `{"name": "a", "quotes": [{"quote": "'"}]}`, "line_comments: a profile needs at least one line or block comment"},
+	}
+	for _, test := range tests {
+		_, err := ParseProfile([]byte(test.def))
+		if err == nil {
+			t.Errorf("%s: expected error %q, got none", test.name, test.err)
+			continue
+		}
+		if _, ok := err.(*ProfileError); !ok {
+			t.Errorf("%s: got %T want *ProfileError", test.name, err)
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: got %q want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestParseProfileFormatErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func([]byte) (*Profile, error)
+		def   string
+		err   string
+	}{
+		{"yamlIndent", ParseProfileYAML, "a:\n  b: c\n d: e\n", "line 3: unexpected indentation"},
+		{"yamlTab", ParseProfileYAML, "name: a\n\tb: c\n", "line 2: tabs can't be used for indentation"},
+		{"yamlFlow", ParseProfileYAML, "name: a\nextensions: [.a\n", "line 2: expected ]"},
+		{"yamlField", ParseProfileYAML, "name: a\nline_comments:\n  - line_start: true\n", "line_comments[0].begin: is required"},
+		// YAML that profiles don't need isn't supported
+		{"yamlAnchor", ParseProfileYAML, "name: &n a\n", "line 1: anchors aren't supported; quote a value that starts with &"},
+		{"yamlAlias", ParseProfileYAML, "name: a\nextensions: [*n]\n", "line 2: aliases aren't supported; quote a value that starts with *"},
+		{"yamlTag", ParseProfileYAML, "name: !!str a\n", "line 1: tags aren't supported; quote a value that starts with !"},
+		{"yamlUnquoted", ParseProfileYAML, "name: a\nline_comments: [{begin: !}]\n", "line 2: tags aren't supported; quote a value that starts with !"},
+		{"yamlLiteral", ParseProfileYAML, "name: |\n  a\n", "line 1: block scalars aren't supported; quote a value that starts with |"},
+		{"yamlFolded", ParseProfileYAML, "name: >\n  a\n", "line 1: block scalars aren't supported; quote a value that starts with >"},
+		{"yamlMultiLine", ParseProfileYAML, "name: a\n  b\n", "line 2: multi-line scalars aren't supported"},
+		{"yamlComplexKey", ParseProfileYAML, "? name\n: a\n", "line 1: complex keys aren't supported; quote a value that starts with ?"},
+		{"yamlDirective", ParseProfileYAML, "%YAML 1.2\n---\nname: a\n", "line 1: directives aren't supported"},
+		{"yamlDocuments", ParseProfileYAML, "name: a\n---\nname: b\n", "line 2: multiple documents aren't supported"},
+		{"yamlDocumentEnd", ParseProfileYAML, "name: a\n...\n", "line 2: document end markers aren't supported"},
+		{"tomlValue", ParseProfileTOML, "name = \"a\"\nextensions = .a\n", "line 2: invalid value \".a\""},
+		{"tomlDuplicate", ParseProfileTOML, "name = \"a\"\nname = \"b\"\n", "line 2: duplicate key name"},
+		{"tomlTrailing", ParseProfileTOML, "name = \"a\" b\n", "line 1: unexpected \"b\""},
+		// TOML that profiles don't need isn't supported
+		{"tomlDate", ParseProfileTOML, "name = \"a\"\nd = 1979-05-27\n", "line 2: dates and times aren't supported"},
+		{"tomlDateTime", ParseProfileTOML, "d = 1979-05-27T07:32:00Z\n", "line 1: dates and times aren't supported"},
+		{"tomlTime", ParseProfileTOML, "d = 07:32:00\n", "line 1: dates and times aren't supported"},
+		{"tomlInf", ParseProfileTOML, "d = inf\n", "line 1: inf and nan aren't supported"},
+		{"tomlNaN", ParseProfileTOML, "d = [1.5, nan]\n", "line 1: inf and nan aren't supported"},
+		{"tomlField", ParseProfileTOML, "name = \"a\"\n[[line_comments]]\nbegin = 1\n", "line_comments[0].begin: must be string, not number"},
+	}
+	for _, test := range tests {
+		_, err := test.parse([]byte(test.def))
+		if err == nil {
+			t.Errorf("%s: expected error %q, got none", test.name, test.err)
+			continue
+		}
+		if _, ok := err.(*ProfileError); !ok {
+			t.Errorf("%s: got %T want *ProfileError", test.name, err)
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: got %q want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nocomment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "load.json")
+	err = ioutil.WriteFile(filename, []byte(`{"name": "test-load", "line_comments": [{"begin": ";"}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadProfile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unregister(p)
+	if LookupProfile("test-load") != p {
+		t.Errorf("profile wasn't registered")
+	}
+	if _, err = LoadProfile(filename); err == nil {
+		t.Errorf("expected an error loading a profile that is already registered")
+	}
+	files := map[string]string{
+		"load.yaml": "name: test-load-yaml\nline_comments: [{begin: ;}]\n",
+		"load.yml":  "name: test-load-yml\nline_comments: [{begin: ;}]\n",
+		"load.toml": "name = \"test-load-toml\"\nline_comments = [{begin = \";\"}]\n",
+	}
+	for name, def := range files {
+		filename := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(filename, []byte(def), 0644); err != nil {
+			t.Fatal(err)
+		}
+		p, err := LoadProfile(filename)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		defer unregister(p)
+		if LookupProfile(p.Name) != p {
+			t.Errorf("%s: profile wasn't registered", name)
+		}
+	}
+}
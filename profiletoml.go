@@ -0,0 +1,408 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseProfileTOML is ParseProfile for a TOML profile definition:
+//
+//	name = "pascal"
+//	extensions = [".pas"]
+//	block_comments = [{begin = "(*", end = "*)"}]
+//
+//	[[line_comments]]
+//	begin = "//"
+//
+//	[[quotes]]
+//	quote = "'"
+//	escape = "'"
+//
+// Tables, arrays of tables, dotted keys, arrays, inline tables, strings,
+// integers, floats, and booleans are supported; dates, times, inf, and nan
+// aren't, and are errors.
+func ParseProfileTOML(data []byte) (*Profile, error) {
+	v, err := parseTOML(data)
+	if err != nil {
+		return nil, &ProfileError{Msg: err.Error()}
+	}
+	return parseProfileValue(v)
+}
+
+// tomlParser parses a TOML document.
+type tomlParser struct {
+	s   string
+	pos int
+}
+
+// parseTOML returns the table defined by the TOML document in data. Values
+// are map[string]interface{}, []interface{}, string, bool, int64, or float64.
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	p := tomlParser{s: string(data)}
+	root := make(map[string]interface{})
+	table := root
+	for {
+		p.skipSpace(true)
+		if p.pos == len(p.s) {
+			return root, nil
+		}
+		if p.s[p.pos] == '[' {
+			array := strings.HasPrefix(p.s[p.pos:], "[[")
+			p.pos++
+			end := "]"
+			if array {
+				p.pos++
+				end = "]]"
+			}
+			keys, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if !strings.HasPrefix(p.s[p.pos:], end) {
+				return nil, p.errorf("expected %s", end)
+			}
+			p.pos += len(end)
+			if table, err = p.table(root, keys, array); err != nil {
+				return nil, err
+			}
+		} else {
+			keys, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if p.pos == len(p.s) || p.s[p.pos] != '=' {
+				return nil, p.errorf("expected = after %s", strings.Join(keys, "."))
+			}
+			p.pos++
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.set(table, keys, v); err != nil {
+				return nil, err
+			}
+		}
+		// only a comment may follow on the line
+		p.skipSpace(false)
+		if p.pos < len(p.s) && p.s[p.pos] != nl && p.s[p.pos] != cr {
+			return nil, p.errorf("unexpected %q", p.s[p.pos:p.lineEnd()])
+		}
+	}
+}
+
+// errorf returns an error at the current line.
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.s[:p.pos], "\n")
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// lineEnd returns the position of the end of the current line.
+func (p *tomlParser) lineEnd() int {
+	if i := strings.IndexAny(p.s[p.pos:], "\r\n"); i >= 0 {
+		return p.pos + i
+	}
+	return len(p.s)
+}
+
+// skipSpace skips spaces, tabs, and comments and, if newlines is set, EOLs.
+func (p *tomlParser) skipSpace(newlines bool) {
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			p.pos = p.lineEnd()
+		case (c == nl || c == cr) && newlines:
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// key parses a key, which may be dotted, and the whitespace around it.
+func (p *tomlParser) key() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace(false)
+		var k string
+		switch {
+		case p.pos == len(p.s):
+			return nil, p.errorf("expected a key")
+		case p.s[p.pos] == '"' || p.s[p.pos] == '\'':
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			k = v.(string)
+		default:
+			i := p.pos
+			for p.pos < len(p.s) && isTOMLBareKeyByte(p.s[p.pos]) {
+				p.pos++
+			}
+			if i == p.pos {
+				return nil, p.errorf("expected a key")
+			}
+			k = p.s[i:p.pos]
+		}
+		keys = append(keys, k)
+		p.skipSpace(false)
+		if p.pos == len(p.s) || p.s[p.pos] != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+// value parses a value and the whitespace before it.
+func (p *tomlParser) value() (interface{}, error) {
+	p.skipSpace(false)
+	if p.pos == len(p.s) {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.s[p.pos]; {
+	case strings.HasPrefix(p.s[p.pos:], `"""`), strings.HasPrefix(p.s[p.pos:], "'''"):
+		delim := p.s[p.pos : p.pos+3]
+		i := strings.Index(p.s[p.pos+3:], delim)
+		if i < 0 {
+			return nil, p.errorf("unterminated string")
+		}
+		s := p.s[p.pos+3 : p.pos+3+i]
+		p.pos += 3 + i + 3
+		// a newline that immediately follows the opening delimiter is trimmed
+		if strings.HasPrefix(s, "\r\n") {
+			s = s[2:]
+		} else if strings.HasPrefix(s, "\n") {
+			s = s[1:]
+		}
+		if delim == "'''" {
+			return s, nil
+		}
+		return p.unquote(strings.Replace(strings.Replace(s, "\r\n", `\n`, -1), "\n", `\n`, -1))
+	case c == '"':
+		for i := p.pos + 1; i < p.lineEnd(); i++ {
+			switch p.s[i] {
+			case '\\':
+				i++
+			case '"':
+				s := p.s[p.pos+1 : i]
+				p.pos = i + 1
+				return p.unquote(s)
+			}
+		}
+		return nil, p.errorf("unterminated string")
+	case c == '\'':
+		i := strings.IndexByte(p.s[p.pos+1:p.lineEnd()], '\'')
+		if i < 0 {
+			return nil, p.errorf("unterminated string")
+		}
+		s := p.s[p.pos+1 : p.pos+1+i]
+		p.pos += i + 2
+		return s, nil
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	}
+	i := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n,]}#", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	s := p.s[i:p.pos]
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	n := strings.Replace(s, "_", "", -1)
+	if v, err := strconv.ParseInt(n, 0, 64); err == nil {
+		return v, nil
+	}
+	p.pos = i
+	if v, err := strconv.ParseFloat(n, 64); err == nil {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, p.errorf("inf and nan aren't supported")
+		}
+		p.pos += len(s)
+		return v, nil
+	}
+	if isTOMLDateTime(s) {
+		return nil, p.errorf("dates and times aren't supported")
+	}
+	return nil, p.errorf("invalid value %q", s)
+}
+
+// unquote returns the basic string s with its escapes replaced.
+func (p *tomlParser) unquote(s string) (interface{}, error) {
+	v, err := strconv.Unquote(`"` + s + `"`)
+	if err != nil {
+		return nil, p.errorf("invalid escape in %q", s)
+	}
+	return v, nil
+}
+
+// array parses an array, which may span lines and contain comments.
+func (p *tomlParser) array() (interface{}, error) {
+	a := []interface{}{}
+	p.pos++
+	for {
+		p.skipSpace(true)
+		if p.pos < len(p.s) && p.s[p.pos] == ']' {
+			p.pos++
+			return a, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+		p.skipSpace(true)
+		switch {
+		case p.pos < len(p.s) && p.s[p.pos] == ',':
+			p.pos++
+		case p.pos == len(p.s) || p.s[p.pos] != ']':
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+// inlineTable parses an inline table, {a = 1, b = 2}.
+func (p *tomlParser) inlineTable() (interface{}, error) {
+	t := make(map[string]interface{})
+	p.pos++
+	p.skipSpace(false)
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return t, nil
+	}
+	for {
+		keys, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.s) || p.s[p.pos] != '=' {
+			return nil, p.errorf("expected = after %s", strings.Join(keys, "."))
+		}
+		p.pos++
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.set(t, keys, v); err != nil {
+			return nil, err
+		}
+		p.skipSpace(false)
+		switch {
+		case p.pos < len(p.s) && p.s[p.pos] == ',':
+			p.pos++
+		case p.pos < len(p.s) && p.s[p.pos] == '}':
+			p.pos++
+			return t, nil
+		default:
+			return nil, p.errorf("expected , or }")
+		}
+	}
+}
+
+// set sets the value of the dotted key, keys, in the table t.
+func (p *tomlParser) set(t map[string]interface{}, keys []string, v interface{}) error {
+	for _, k := range keys[:len(keys)-1] {
+		switch sub := t[k].(type) {
+		case nil:
+			m := make(map[string]interface{})
+			t[k] = m
+			t = m
+		case map[string]interface{}:
+			t = sub
+		default:
+			return p.errorf("%s isn't a table", k)
+		}
+	}
+	k := keys[len(keys)-1]
+	if _, dup := t[k]; dup {
+		return p.errorf("duplicate key %s", strings.Join(keys, "."))
+	}
+	t[k] = v
+	return nil
+}
+
+// table returns the table that a [keys] header, or if array is set a
+// [[keys]] header, defines, creating it. A key that names an array of tables
+// refers to its last table.
+func (p *tomlParser) table(root map[string]interface{}, keys []string, array bool) (map[string]interface{}, error) {
+	t := root
+	for i, k := range keys {
+		last := i == len(keys)-1
+		switch sub := t[k].(type) {
+		case nil:
+			m := make(map[string]interface{})
+			if last && array {
+				t[k] = []interface{}{m}
+			} else {
+				t[k] = m
+			}
+			t = m
+		case map[string]interface{}:
+			if last && array {
+				return nil, p.errorf("%s isn't an array of tables", strings.Join(keys, "."))
+			}
+			t = sub
+		case []interface{}:
+			var m map[string]interface{}
+			if len(sub) > 0 {
+				m, _ = sub[len(sub)-1].(map[string]interface{})
+			}
+			if m == nil {
+				return nil, p.errorf("%s isn't a table", strings.Join(keys[:i+1], "."))
+			}
+			if last && array {
+				m = make(map[string]interface{})
+				t[k] = append(sub, m)
+			} else if last {
+				return nil, p.errorf("%s is an array of tables", strings.Join(keys, "."))
+			}
+			t = m
+		default:
+			return nil, p.errorf("%s isn't a table", strings.Join(keys[:i+1], "."))
+		}
+	}
+	return t, nil
+}
+
+// isTOMLDateTime returns whether s starts like a date, 1979-05-27, or a
+// time, 07:32:00.
+func isTOMLDateTime(s string) bool {
+	digits := func(s string, n int) bool {
+		if len(s) < n {
+			return false
+		}
+		for i := 0; i < n; i++ {
+			if s[i] < '0' || s[i] > '9' {
+				return false
+			}
+		}
+		return true
+	}
+	return (digits(s, 4) && len(s) > 4 && s[4] == '-') || (digits(s, 2) && len(s) > 2 && s[2] == ':')
+}
+
+// isTOMLBareKeyByte returns whether c may be part of a bare key.
+func isTOMLBareKeyByte(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,486 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseProfileYAML is ParseProfile for a YAML profile definition:
+//
+//	name: pascal
+//	extensions: [.pas]
+//	line_comments:
+//	  - begin: //
+//	block_comments:
+//	  - {begin: "(*", end: "*)"}
+//	quotes:
+//	  - quote: "'"
+//	    escape: "'"
+//
+// The YAML that profile definitions need is supported: block and flow
+// mappings and sequences, and plain and quoted scalars. Anchors, aliases,
+// tags, block and multi-line scalars, complex keys, directives, and multiple
+// documents aren't; they are errors, so a delimiter that starts with one of
+// their indicators, e.g. ! or *, must be quoted. As in YAML, a # that
+// follows whitespace starts a comment, so a # delimiter must be quoted too.
+func ParseProfileYAML(data []byte) (*Profile, error) {
+	v, err := parseYAML(data)
+	if err != nil {
+		return nil, &ProfileError{Msg: err.Error()}
+	}
+	return parseProfileValue(v)
+}
+
+// yamlUnsupported names the unsupported YAML syntax that each indicator
+// starts, when it starts a scalar or key.
+var yamlUnsupported = map[byte]string{
+	'&': "anchors",
+	'*': "aliases",
+	'!': "tags",
+	'|': "block scalars",
+	'>': "block scalars",
+	'%': "directives",
+	'?': "complex keys",
+	'@': "reserved indicators",
+	'`': "reserved indicators",
+}
+
+// yamlLine is a line of a YAML document, without its comment.
+type yamlLine struct {
+	num    int // the line number
+	indent int
+	text   string // the line without its indentation
+}
+
+// yamlParser parses a YAML document's lines.
+type yamlParser struct {
+	lines []yamlLine
+	i     int // the line being parsed
+}
+
+// parseYAML returns the value of the YAML document in data: a
+// map[string]interface{}, []interface{}, string, bool, int64, or nil.
+func parseYAML(data []byte) (interface{}, error) {
+	var p yamlParser
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(stripYAMLComment(line), " \t\r")
+		text := strings.TrimLeft(line, " ")
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, "\t"):
+			return nil, fmt.Errorf("line %d: tabs can't be used for indentation", i+1)
+		case text == "---" && len(line) == 3:
+			if len(p.lines) > 0 {
+				return nil, fmt.Errorf("line %d: multiple documents aren't supported", i+1)
+			}
+			continue
+		case text[0] == '%' && len(line) == len(text):
+			return nil, fmt.Errorf("line %d: directives aren't supported", i+1)
+		case text == "..." && len(line) == 3:
+			return nil, fmt.Errorf("line %d: document end markers aren't supported", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(line) - len(text), text: text})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.node(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.i].num)
+	}
+	return v, nil
+}
+
+// node parses the node that starts at the current line, whose indentation is
+// indent.
+func (p *yamlParser) node(indent int) (interface{}, error) {
+	l := p.lines[p.i]
+	if isYAMLSeqItem(l.text) {
+		return p.sequence(indent)
+	}
+	if _, _, ok, err := splitYAMLKey(l.text, l.num); err != nil || ok {
+		if err != nil {
+			return nil, err
+		}
+		return p.mapping(indent)
+	}
+	p.i++
+	return p.value(l.text, l.num)
+}
+
+// sequence parses a block sequence whose items are indented by indent.
+func (p *yamlParser) sequence(indent int) (interface{}, error) {
+	seq := []interface{}{}
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent && isYAMLSeqItem(p.lines[p.i].text) {
+		l := p.lines[p.i]
+		rest := strings.TrimLeft(l.text[1:], " ")
+		var v interface{}
+		var err error
+		if rest == "" {
+			p.i++
+			if p.i < len(p.lines) && p.lines[p.i].indent > indent {
+				v, err = p.node(p.lines[p.i].indent)
+			}
+		} else {
+			// the item is a node that starts after the -, e.g. a mapping
+			// whose other keys are on the lines that follow
+			col := indent + len(l.text) - len(rest)
+			p.lines[p.i] = yamlLine{num: l.num, indent: col, text: rest}
+			v, err = p.node(col)
+		}
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+	}
+	return seq, nil
+}
+
+// mapping parses a block mapping whose keys are indented by indent.
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	m := make(map[string]interface{})
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent && !isYAMLSeqItem(p.lines[p.i].text) {
+		l := p.lines[p.i]
+		key, rest, ok, err := splitYAMLKey(l.text, l.num)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a key", l.num)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.num, key)
+		}
+		p.i++
+		var v interface{}
+		switch {
+		case rest != "":
+			v, err = p.value(rest, l.num)
+			if err == nil && p.i < len(p.lines) && p.lines[p.i].indent > indent {
+				err = fmt.Errorf("line %d: multi-line scalars aren't supported", p.lines[p.i].num)
+			}
+		case p.i < len(p.lines) && p.lines[p.i].indent > indent:
+			v, err = p.node(p.lines[p.i].indent)
+		case p.i < len(p.lines) && p.lines[p.i].indent == indent && isYAMLSeqItem(p.lines[p.i].text):
+			// a sequence may have the same indentation as its key
+			v, err = p.sequence(indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// value parses s, the scalar or flow collection on line num. A flow
+// collection may continue on the lines that follow.
+func (p *yamlParser) value(s string, num int) (interface{}, error) {
+	if s[0] == '[' || s[0] == '{' {
+		for yamlFlowDepth(s) > 0 && p.i < len(p.lines) {
+			s += " " + p.lines[p.i].text
+			p.i++
+		}
+	}
+	f := yamlFlow{s: s, num: num}
+	v, err := f.value(false)
+	if err != nil {
+		return nil, err
+	}
+	if f.skipSpace(); f.pos < len(f.s) {
+		return nil, fmt.Errorf("line %d: unexpected %q", num, f.s[f.pos:])
+	}
+	return v, nil
+}
+
+// yamlFlow parses a scalar or flow collection.
+type yamlFlow struct {
+	s   string
+	pos int
+	num int // the line number, for errors
+}
+
+// skipSpace skips spaces and tabs.
+func (f *yamlFlow) skipSpace() {
+	for f.pos < len(f.s) && (f.s[f.pos] == ' ' || f.s[f.pos] == '\t') {
+		f.pos++
+	}
+}
+
+// value parses a value. In a flow collection, inFlow is true and plain
+// scalars end at a flow indicator.
+func (f *yamlFlow) value(inFlow bool) (interface{}, error) {
+	f.skipSpace()
+	if f.pos == len(f.s) {
+		return nil, nil
+	}
+	switch f.s[f.pos] {
+	case '[':
+		return f.sequence()
+	case '{':
+		return f.mapping()
+	case '"', '\'':
+		return f.quoted()
+	}
+	if err := f.unsupported(); err != nil {
+		return nil, err
+	}
+	return resolveYAMLScalar(f.plain(inFlow, false)), nil
+}
+
+// unsupported returns an error if the scalar at the current position starts
+// with the indicator of unsupported syntax; see yamlUnsupported.
+func (f *yamlFlow) unsupported() error {
+	c := f.s[f.pos]
+	if what, ok := yamlUnsupported[c]; ok {
+		return fmt.Errorf("line %d: %s aren't supported; quote a value that starts with %c", f.num, what, c)
+	}
+	return nil
+}
+
+// sequence parses a flow sequence, [a, b].
+func (f *yamlFlow) sequence() (interface{}, error) {
+	seq := []interface{}{}
+	f.pos++
+	for {
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == ']' {
+			f.pos++
+			return seq, nil
+		}
+		v, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+		if err := f.next(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// mapping parses a flow mapping, {a: b, c: d}.
+func (f *yamlFlow) mapping() (interface{}, error) {
+	m := make(map[string]interface{})
+	f.pos++
+	for {
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		var key string
+		if f.pos < len(f.s) && (f.s[f.pos] == '"' || f.s[f.pos] == '\'') {
+			k, err := f.quoted()
+			if err != nil {
+				return nil, err
+			}
+			key = k.(string)
+		} else {
+			key = f.plain(true, true)
+		}
+		f.skipSpace()
+		if f.pos == len(f.s) || f.s[f.pos] != ':' {
+			return nil, fmt.Errorf("line %d: expected : after key %q", f.num, key)
+		}
+		f.pos++
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", f.num, key)
+		}
+		v, err := f.value(true)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+		if err := f.next('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// next consumes the , that separates the items of a flow collection; the
+// collection's closing bracket, end, is left for the caller.
+func (f *yamlFlow) next(end byte) error {
+	f.skipSpace()
+	switch {
+	case f.pos == len(f.s):
+		return fmt.Errorf("line %d: expected %c", f.num, end)
+	case f.s[f.pos] == ',':
+		f.pos++
+	case f.s[f.pos] != end:
+		return fmt.Errorf("line %d: expected , or %c", f.num, end)
+	}
+	return nil
+}
+
+// plain returns a plain scalar. It ends at the end of the text, at a : that
+// is followed by a space or is the last character, and, in a flow
+// collection, at a flow indicator. A key also ends at any :.
+func (f *yamlFlow) plain(inFlow, key bool) string {
+	i := f.pos
+	for ; f.pos < len(f.s); f.pos++ {
+		c := f.s[f.pos]
+		if inFlow && strings.IndexByte(",[]{}", c) >= 0 {
+			break
+		}
+		if c == ':' && (key || f.pos+1 == len(f.s) || f.s[f.pos+1] == ' ' || (inFlow && strings.IndexByte(",[]{}", f.s[f.pos+1]) >= 0)) {
+			break
+		}
+	}
+	return strings.TrimRight(f.s[i:f.pos], " \t")
+}
+
+// quoted returns a single or double quoted scalar. In a single quoted
+// scalar, a doubled quote is a quote; a double quoted scalar has backslash
+// escapes.
+func (f *yamlFlow) quoted() (interface{}, error) {
+	q := f.s[f.pos]
+	var b strings.Builder
+	for i := f.pos + 1; i < len(f.s); i++ {
+		c := f.s[i]
+		switch {
+		case q == '\'' && c == q && i+1 < len(f.s) && f.s[i+1] == q:
+			b.WriteByte(q)
+			i++
+		case q == '"' && c == '\\' && i+1 < len(f.s):
+			b.WriteByte(c)
+			b.WriteByte(f.s[i+1])
+			i++
+		case c == q:
+			f.pos = i + 1
+			if q == '\'' {
+				return b.String(), nil
+			}
+			s, err := strconv.Unquote(`"` + strings.Replace(b.String(), `\/`, "/", -1) + `"`)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid escape in %s", f.num, f.s[:i+1])
+			}
+			return s, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return nil, fmt.Errorf("line %d: unterminated quoted scalar", f.num)
+}
+
+// resolveYAMLScalar returns the value of a plain scalar: a bool, null, an
+// integer, or a string.
+func resolveYAMLScalar(s string) interface{} {
+	switch s {
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	return s
+}
+
+// isYAMLSeqItem returns whether the text of a line is a block sequence item.
+func isYAMLSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits the text of line num, key: rest, into its key and the
+// rest. If it isn't a key, ok is false.
+func splitYAMLKey(text string, num int) (key, rest string, ok bool, err error) {
+	f := yamlFlow{s: text, num: num}
+	switch text[0] {
+	case '[', '{', '-':
+		return "", "", false, nil
+	case '"', '\'':
+		k, err := f.quoted()
+		if err != nil {
+			return "", "", false, err
+		}
+		key = k.(string)
+		f.skipSpace()
+	default:
+		if err := f.unsupported(); err != nil {
+			return "", "", false, err
+		}
+		key = f.plain(false, false)
+	}
+	if f.pos == len(f.s) || f.s[f.pos] != ':' || (f.pos+1 < len(f.s) && f.s[f.pos+1] != ' ') {
+		return "", "", false, nil
+	}
+	return key, strings.TrimLeft(f.s[f.pos+1:], " "), true, nil
+}
+
+// stripYAMLComment returns the line without its comment: a # at the start of
+// the line, or after whitespace, that isn't quoted.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		case (c == '"' || c == '\'') && atYAMLScalarStart(line, i):
+			quote = c
+		}
+	}
+	return line
+}
+
+// yamlFlowDepth returns how many flow collections in s are still open.
+func yamlFlowDepth(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case (c == '"' || c == '\'') && atYAMLScalarStart(s, i):
+			quote = c
+		}
+	}
+	return depth
+}
+
+// atYAMLScalarStart returns whether a scalar may start at s[i]: a quote
+// elsewhere, e.g. in it's, is just a character.
+func atYAMLScalarStart(s string, i int) bool {
+	j := i - 1
+	for j >= 0 && (s[j] == ' ' || s[j] == '\t') {
+		j--
+	}
+	return j < 0 || strings.IndexByte(":-,[{?", s[j]) >= 0
+}
@@ -0,0 +1,100 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"regexp"
+)
+
+// Python is the profile for Python: # line comments. Strings, including
+// triple quoted strings and those with prefixes, e.g. r"" and f"", are not
+// scanned for comments. A #! line at the start of the input is not a
+// comment.
+//
+// Encoding declarations, e.g. # -*- coding: utf-8 -*-, on the first two
+// lines are directive comments.
+var Python = &Profile{
+	Name:         "python",
+	Aliases:      []string{"py", "python3"},
+	Extensions:   []string{".py", ".pyw", ".pyi"},
+	Filenames:    []string{"SConstruct", "SConscript"},
+	Interpreters: []string{"python", "pypy"},
+	Hints:        []string{"def ", "import ", "self.", "elif ", "__init__", "None", "print(", "    return"},
+	lexText:      lexPython,
+}
+
+// pythonEncoding matches an encoding declaration, see PEP 263.
+var pythonEncoding = regexp.MustCompile(`^#.*?coding[:=][ \t]*[-\w.]+`)
+
+// lexPython lexes Python.
+func lexPython(l *lexer) stateFn {
+	if l.pos == 0 && l.hasPrefix("#!") {
+		l.pos = l.lineEnd(l.pos)
+	}
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '#':
+			typ := tokenShellComment
+			if l.atPythonEncoding() {
+				typ = tokenDirectiveComment
+			}
+			l.lexLineComment(typ)
+		case l.hasPrefix(`"""`) || l.hasPrefix("'''"):
+			if !l.lexTripleQuoted() {
+				return l.errorf("unterminated triple-quoted string")
+			}
+		case c == '"' || c == '\'':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atPythonEncoding returns whether the comment at the current position is
+// an encoding declaration: it is the first thing on one of the first two
+// lines.
+func (l *lexer) atPythonEncoding() bool {
+	if !l.atFirstNonSpace() {
+		return false
+	}
+	lines := 0
+	for _, c := range l.input[:l.pos] {
+		if c == nl {
+			lines++
+		}
+	}
+	return lines < 2 && pythonEncoding.Match(l.input[l.pos:l.lineEnd(l.pos)])
+}
+
+// lexTripleQuoted consumes a triple quoted string and emits it as quoted
+// text. If the string isn't terminated, false is returned.
+func (l *lexer) lexTripleQuoted() bool {
+	quote := string(l.input[l.pos : l.pos+3])
+	l.emitText()
+	for l.pos += 3; int(l.pos) < len(l.input); l.pos++ {
+		switch {
+		case l.input[l.pos] == '\\':
+			l.pos++
+		case l.hasPrefix(quote):
+			l.pos += 3
+			l.emit(tokenQuotedText)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestPython(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "#!/usr/bin/env python3\n# a\nb = 1  # c\n", "#!/usr/bin/env python3\n\nb = 1  \n", ""},
+		{"strings", "a = \"# b\" + '# c \\\\' + r'\\'#' # d\n", "a = \"# b\" + '# c \\\\' + r'\\'#' \n", ""},
+		{"triple", "a = \"\"\"\n# b \\\"\"\"\n\"\"\" # c\nd = '''#'''\n", "a = \"\"\"\n# b \\\"\"\"\n\"\"\" \nd = '''#'''\n", ""},
+		{"docstring", "def a():\n    \"\"\"b # c\"\"\"\n    pass # d\n", "def a():\n    \"\"\"b # c\"\"\"\n    pass \n", ""},
+		{"fstring", "a = f\"{b!r} # c\" # d\n", "a = f\"{b!r} # c\" \n", ""},
+		{"encoding", "# -*- coding: utf-8 -*-\n# a\n", "\n\n", ""},
+		{"unclosedString", "a = 'b", "", "index 4: unterminated quoted string"},
+		{"unclosedTriple", "a = \"\"\"b\"\"", "", "index 4: unterminated triple-quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Python}, tests)
+
+	keep := []profileTest{
+		{"encoding", "#!/usr/bin/python\n# vim: set fileencoding=utf-8 :\n# a\n# coding: latin-1\n", "#!/usr/bin/python\n# vim: set fileencoding=utf-8 :\n\n\n", ""},
+		{"notFirst", "a = 1 # coding: utf-8\n", "a = 1 \n", ""},
+	}
+	testProfile(t, Stripper{Profile: Python, KeepDirectiveComments: true}, keep)
+}
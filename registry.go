@@ -0,0 +1,188 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// registry holds the registered profiles in the order they were registered.
+var registry = struct {
+	sync.RWMutex
+	profiles []*Profile
+}{}
+
+// modelineLines is the number of lines at the start of the input that are
+// searched for a modeline.
+const modelineLines = 5
+
+var (
+	// vim: ft=sh, vim: set filetype=python :, vi:, and ex:
+	vimModeline = regexp.MustCompile(`(?:^|\s)(?:vi|vim|ex):.*\b(?:ft|filetype)=([\w+-]+)`)
+	// -*- mode: python -*- and -*- python -*-
+	emacsModeline = regexp.MustCompile(`-\*-\s*(?:.*?mode:\s*)?([\w+-]+)\s*(?:;.*)?-\*-`)
+)
+
+func init() {
+	for _, p := range []*Profile{
+		Batch, C, CPP, CSS, Clojure, Dockerfile, EmacsLisp, Env, Erlang, Go,
+		GoTemplate, HCL, HTML, Handlebars, INI, JavaScript, Jinja, LaTeX, Lua,
+		Makefile, MySQL, PHP, Perl, PostgreSQL, PowerShell, Properties, Python,
+		Ruby, Rust, SQL, Scheme, Shell, TypeScript, VBScript, XML,
+	} {
+		if err := Register(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register makes a profile available by its name and aliases, and to
+// DetectProfile. If more than one registered profile matches a file, the one
+// registered last is used, so built-in profiles can be overridden. An error
+// is returned if the profile doesn't have a name or if a profile with the
+// same name has already been registered.
+func Register(p *Profile) error {
+	if p.Name == "" {
+		return fmt.Errorf("profile has no name")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	for _, v := range registry.profiles {
+		if strings.EqualFold(v.Name, p.Name) {
+			return fmt.Errorf("profile %q is already registered", p.Name)
+		}
+	}
+	registry.profiles = append(registry.profiles, p)
+	return nil
+}
+
+// LookupProfile returns the registered profile whose name or alias is name,
+// which is matched without regard to case. If there isn't one, nil is
+// returned.
+func LookupProfile(name string) *Profile {
+	return findProfile(func(p *Profile) bool {
+		return strings.EqualFold(p.Name, name) || containsFold(p.Aliases, name)
+	})
+}
+
+// DetectProfile returns the registered profile for the named file, whose
+// content starts with head. The profile is chosen by, in order: a vim or
+// Emacs modeline in the first lines of head, the file's name, its extension,
+// and its #! line. If no profile matches, nil is returned.
+func DetectProfile(filename string, head []byte) *Profile {
+	if name := modeline(head); name != "" {
+		if p := LookupProfile(name); p != nil {
+			return p
+		}
+	}
+	base := filepath.Base(filename)
+	if p := findProfile(func(p *Profile) bool { return contains(p.Filenames, base) }); p != nil {
+		return p
+	}
+	if ext := strings.ToLower(filepath.Ext(base)); ext != "" {
+		if p := findProfile(func(p *Profile) bool { return contains(p.Extensions, ext) }); p != nil {
+			return p
+		}
+	}
+	if cmd := interpreter(head); cmd != "" {
+		trimmed := strings.TrimRight(cmd, "0123456789.")
+		return findProfile(func(p *Profile) bool {
+			return contains(p.Interpreters, cmd) || contains(p.Interpreters, trimmed)
+		})
+	}
+	return nil
+}
+
+// findProfile returns the last registered profile for which match returns
+// true. If there isn't one, nil is returned.
+func findProfile(match func(p *Profile) bool) *Profile {
+	registry.RLock()
+	defer registry.RUnlock()
+	for i := len(registry.profiles) - 1; i >= 0; i-- {
+		if match(registry.profiles[i]) {
+			return registry.profiles[i]
+		}
+	}
+	return nil
+}
+
+// modeline returns the file type set by a vim or Emacs modeline in the first
+// lines of head. If there isn't one, an empty string is returned.
+func modeline(head []byte) string {
+	for i, line := range bytes.SplitN(head, []byte{nl}, modelineLines+1) {
+		if i == modelineLines {
+			break
+		}
+		if m := vimModeline.FindSubmatch(line); m != nil {
+			return string(m[1])
+		}
+		if m := emacsModeline.FindSubmatch(line); m != nil {
+			return strings.TrimSuffix(string(m[1]), "-mode")
+		}
+	}
+	return ""
+}
+
+// interpreter returns the name of the command in head's #! line, e.g.
+// python3 for #!/usr/bin/env python3. If there isn't a #! line, an empty
+// string is returned.
+func interpreter(head []byte) string {
+	if !bytes.HasPrefix(head, []byte("#!")) {
+		return ""
+	}
+	line := head[2:]
+	if i := bytes.IndexByte(line, nl); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	cmd := filepath.Base(fields[0])
+	if cmd != "env" {
+		return cmd
+	}
+	// env's options and variable assignments precede the command
+	for _, f := range fields[1:] {
+		if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+			return filepath.Base(f)
+		}
+	}
+	return ""
+}
+
+// contains returns whether s is in list.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold returns whether s is in list, without regard to case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
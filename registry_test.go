@@ -0,0 +1,120 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestDetectProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		head     string
+		profile  *Profile
+	}{
+		{"none", "a.txt", "a", nil},
+		{"empty", "", "", nil},
+		{"go", "a/b.go", "package b", Go},
+		{"python", "a.py", "", Python},
+		{"extensionCase", "A.PY", "", Python},
+		{"dockerfile", "a/Dockerfile", "FROM a", Dockerfile},
+		{"makefile", "GNUmakefile", "", Makefile},
+		{"env", ".env", "", Env},
+		{"shebang", "a", "#!/bin/sh\n", Shell},
+		{"shebangEnv", "a", "#!/usr/bin/env python3\n", Python},
+		{"shebangVersion", "a", "#!/usr/bin/python3.11 -u\n", Python},
+		{"shebangEnvOptions", "a", "#!/usr/bin/env -S A=1 node --b\n", JavaScript},
+		{"shebangUnknown", "a", "#!/usr/bin/awk -f\n", nil},
+		{"vim", "a", "a\n# vim: ft=sh\n", Shell},
+		{"vimSet", "a.txt", "/* vim: set ts=4 filetype=cpp : */", CPP},
+		{"vimOverrides", "a.py", "# vi: ft=make\n", Makefile},
+		{"vimUnknown", "a.py", "# vim: ft=nope\n", Python},
+		{"vimLate", "a", "\n\n\n\n\n# vim: ft=sh\n", nil},
+		{"emacs", "a", "# -*- mode: python; coding: utf-8 -*-\n", Python},
+		{"emacsShort", "a", "/* -*- C -*- */\n", C},
+		{"emacsCoding", "a.rb", "# -*- coding: utf-8 -*-\n", Ruby},
+		{"index", "a.rb", "index: ft=sh\n", Ruby},
+	}
+	for _, test := range tests {
+		p := DetectProfile(test.filename, []byte(test.head))
+		if p != test.profile {
+			t.Errorf("%s: got %v want %v", test.name, profileName(p), profileName(test.profile))
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(&Profile{Name: "GO"}); err == nil {
+		t.Errorf("expected an error registering a profile whose name is taken")
+	}
+	if err := Register(&Profile{}); err == nil {
+		t.Errorf("expected an error registering a profile without a name")
+	}
+	p := &Profile{Name: "test-register", Aliases: []string{"test-alias"}, Extensions: []string{".rb"}, lexText: lexRuby}
+	if err := Register(p); err != nil {
+		t.Fatal(err)
+	}
+	defer unregister(p)
+	if LookupProfile("Test-Alias") != p {
+		t.Errorf("alias lookup: got %v want %v", profileName(LookupProfile("Test-Alias")), p.Name)
+	}
+	// the profile registered last wins
+	if got := DetectProfile("a.rb", nil); got != p {
+		t.Errorf("detect: got %v want %v", profileName(got), p.Name)
+	}
+	// a name wins over another profile's alias
+	if got := LookupProfile("python3"); got != Python {
+		t.Errorf("lookup: got %v want %v", profileName(got), Python.Name)
+	}
+}
+
+func TestCleanFile(t *testing.T) {
+	var s Stripper
+	b, err := s.CleanFile("a.lua", []byte("a = 1 -- b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "a = 1 \n" {
+		t.Errorf("got %q want %q", b, "a = 1 \n")
+	}
+	if s.Profile != nil {
+		t.Errorf("CleanFile set the Stripper's profile")
+	}
+	b, err = s.CleanFile("a.unknown", []byte("a // b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "a " {
+		t.Errorf("got %q want %q", b, "a ")
+	}
+}
+
+// unregister removes a profile registered by a test.
+func unregister(p *Profile) {
+	registry.Lock()
+	defer registry.Unlock()
+	for i, v := range registry.profiles {
+		if v == p {
+			registry.profiles = append(registry.profiles[:i], registry.profiles[i+1:]...)
+			return
+		}
+	}
+}
+
+func profileName(p *Profile) string {
+	if p == nil {
+		return "nil"
+	}
+	return p.Name
+}
@@ -0,0 +1,121 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Comment is a comment found in the input.
+type Comment struct {
+	Type CommentType
+	// Text is the comment, including its delimiters. With the default rules,
+	// a line comment includes its EOL.
+	Text string
+	// Pos is the position of the comment in the input.
+	Pos Pos
+}
+
+// replace returns the replacement for the comment token, t, that was found
+// in the input, in the embedded code e, if it isn't nil. If the comment
+// included its EOL, the replacement keeps it. An error is returned if the
+// replacement isn't valid; see validReplacement.
+func (s *Stripper) replace(input []byte, t token, e *embedding) ([]byte, error) {
+	r := s.Replace(Comment{Type: commentType(t.typ), Text: t.value, Pos: t.pos})
+	if len(r) == 0 {
+		return nil, nil
+	}
+	if eol := trailingEOL(t.value); eol != "" && !strings.HasSuffix(string(r), eol) {
+		r = append(r[:len(r):len(r)], eol...)
+	}
+	if !s.validReplacement(input, e, int(t.pos), int(t.pos)+len(t.value), r) {
+		return nil, token{tokenError, t.pos, fmt.Sprintf("replacement for %s is not a comment: %q", commentType(t.typ), r)}
+	}
+	return r, nil
+}
+
+// validReplacement returns whether r can replace the comments in
+// input[start:end]: lexed on its own, it must be nothing but comments and
+// whitespace, and if the comments are followed by text on the same line, r
+// mustn't run into it, e.g. by replacing a block comment with a line
+// comment. Comments in embedded code, e, e.g. JavaScript in HTML, are
+// checked by the rules of that code, and r mustn't contain the text that
+// ends it, e.g. </script. If the comments, lexed on their own, aren't
+// comments, r can't be checked and isn't valid.
+func (s *Stripper) validReplacement(input []byte, e *embedding, start, end int, r []byte) bool {
+	p := s.profile()
+	followed := end < len(input) && input[end] != nl && input[end] != cr
+	if e != nil {
+		if bytes.Contains(bytes.ToLower(r), []byte(e.close)) {
+			return false
+		}
+		p = e.profile
+		if n := len(e.close); followed && end+n <= len(input) && bytes.EqualFold(input[end:end+n], []byte(e.close)) {
+			followed = false
+		}
+	}
+	if !onlyComments(input[start:end], p, false) {
+		return false
+	}
+	if followed {
+		r = append(r[:len(r):len(r)], " x"...)
+	}
+	return onlyComments(r, p, followed)
+}
+
+// onlyComments returns whether b, lexed using the rules of p, is nothing but
+// comments and whitespace. If probe is set, b ends with an x, which must be
+// text.
+func onlyComments(b []byte, p *Profile, probe bool) bool {
+	l := lexProfile(b, p)
+	defer l.drain()
+	// text is the last text token, which may be the probe
+	var text string
+	for {
+		t := l.nextToken()
+		switch t.typ {
+		case tokenEOF:
+			if probe {
+				return strings.TrimSpace(text) == "x"
+			}
+			return strings.TrimSpace(text) == ""
+		case tokenError:
+			return false
+		}
+		if strings.TrimSpace(text) != "" {
+			return false
+		}
+		switch {
+		case t.typ == tokenText:
+			text = t.value
+		case commentType(t.typ) == none:
+			return false
+		default:
+			text = ""
+		}
+	}
+}
+
+// trailingEOL returns the EOL that s ends with, if any.
+func trailingEOL(s string) string {
+	switch {
+	case strings.HasSuffix(s, "\r\n"):
+		return "\r\n"
+	case strings.HasSuffix(s, "\n"):
+		return "\n"
+	}
+	return ""
+}
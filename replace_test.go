@@ -0,0 +1,118 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReplace(t *testing.T) {
+	redact := func(c Comment) []byte {
+		switch c.Type {
+		case CComment:
+			return []byte("/* redacted */")
+		case CPPComment:
+			return []byte("// redacted")
+		}
+		return nil
+	}
+	marker := func(s string) func(Comment) []byte {
+		return func(Comment) []byte { return []byte(s) }
+	}
+	tests := []struct {
+		name    string
+		profile *Profile
+		input   string
+		replace func(Comment) []byte
+		output  string
+		err     string
+	}{
+		{"redact", Go, "a /* b */ c // d\n", redact, "a /* redacted */ c // redacted\n", ""},
+		{"elide", SQL, "a -- b\n/* c */\n", redact, "a \n/* redacted */\n", ""},
+		{"keepEOL", nil, "a // b\r\nc // d\n", marker("// x"), "a // x\r\nc // x\n", ""},
+		{"hasEOL", nil, "a // b\nc", marker("/* x */\n"), "a /* x */\nc", ""},
+		{"lineForBlock", Go, "a /* b */\nc", marker("// x"), "a // x\nc", ""},
+		{"multiple", Shell, "a # b\n", marker("# x  # y"), "a # x  # y\n", ""},
+		{"script", HTML, "<script>a // b\n</script>", marker("// x"), "<script>a // x\n</script>", ""},
+		{"scriptClose", HTML, "<script>a // b\n</script>", marker("// </script><b>x</b>"), "", "index 10: replacement for C++ comment is not a comment: \"// </script><b>x</b>\""},
+		{"styleClose", HTML, "<style>a /* b */ {}</style>", marker("/* </style> */"), "", "index 9: replacement for C comment is not a comment: \"/* </style> */\""},
+		{"scriptSameLine", HTML, "<script>a /* b */</script>", marker("// x"), "<script>a // x</script>", ""},
+		{"php", PHP, "<?php a // b\n?>", marker("# x"), "<?php a # x\n?>", ""},
+		{"phpClose", PHP, "<?php a /* b */ ?>", marker("/* ?> */"), "", "index 8: replacement for C comment is not a comment: \"/* ?> */\""},
+		{"notComment", Go, "a // b\n", marker("x"), "", "index 2: replacement for C++ comment is not a comment: \"x\""},
+		{"lineEOL", Go, "a // b\nc\n", marker("// x\ny"), "", "index 2: replacement for C++ comment is not a comment: \"// x\\ny\""},
+		{"runsInto", Go, "a /* b */ c\n", marker("// x"), "", "index 2: replacement for C comment is not a comment: \"// x\""},
+		{"unclosed", Go, "a /* b */\n", marker("/* x"), "", "index 2: replacement for C comment is not a comment: \"/* x\""},
+	}
+	for _, test := range tests {
+		s := Stripper{Profile: test.profile, Replace: test.replace}
+		result, err := s.Clean([]byte(test.input))
+		if err != nil {
+			if err.Error() != test.err {
+				t.Errorf("%s: got %q want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if test.err != "" {
+			t.Errorf("%s: got no error; wanted %q", test.name, test.err)
+			continue
+		}
+		if string(result) != test.output {
+			t.Errorf("%s: got %q want %q", test.name, string(result), test.output)
+		}
+	}
+}
+
+func TestReplaceComments(t *testing.T) {
+	var got []string
+	s := Stripper{
+		Profile:               Go,
+		KeepDirectiveComments: true,
+		Replace: func(c Comment) []byte {
+			got = append(got, fmt.Sprintf("%d %s %q", c.Pos, c.Type, c.Text))
+			return nil
+		},
+	}
+	_, err := s.Clean([]byte("//go:build a\n\n// b\npackage c /* d */\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`14 C++ comment "// b"`, `29 C comment "/* d */"`}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestReplaceErrorStopsLexer(t *testing.T) {
+	before := runtime.NumGoroutine()
+	s := Stripper{Profile: Go, Replace: func(Comment) []byte { return []byte("x") }}
+	input := []byte("a // b\nc // d\ne // f\n")
+	for i := 0; i < 100; i++ {
+		if _, err := s.Clean(input); err == nil {
+			t.Fatal("expected an error for a replacement that isn't a comment")
+		}
+	}
+	// the lexers' goroutines exit once their tokens are drained
+	n := runtime.NumGoroutine()
+	for i := 0; i < 100 && n > before; i++ {
+		time.Sleep(time.Millisecond)
+		n = runtime.NumGoroutine()
+	}
+	if n > before {
+		t.Errorf("got %d goroutines want at most %d", n, before)
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// sidecarVersion is the version of the sidecar format.
+const sidecarVersion = 1
+
+// sidecarFile records the comments removed by StripReversible.
+type sidecarFile struct {
+	Version int            `json:"version"`
+	Entries []sidecarEntry `json:"entries"`
+}
+
+// sidecarEntry records the comments removed from, and before, a line of the
+// code: its anchor. The anchor is found again by the hashes of its text and
+// of the lines around it.
+type sidecarEntry struct {
+	// Line is the index of the anchor in the code.
+	Line int `json:"line"`
+	// EOF is set if the comments follow the last line of the code.
+	EOF bool `json:"eof,omitempty"`
+	// Hash is the hash of the anchor, ignoring whitespace, and Exact is the
+	// hash of it as is.
+	Hash  string `json:"hash,omitempty"`
+	Exact string `json:"exact,omitempty"`
+	// Prev and Next are the hashes of the closest lines before and after the
+	// anchor that aren't blank.
+	Prev string `json:"prev,omitempty"`
+	Next string `json:"next,omitempty"`
+	// Before are the lines, including their EOLs, that only had comments and
+	// were removed from before the anchor.
+	Before string `json:"before,omitempty"`
+	// Orig is the anchor as it was, if comments were removed from it.
+	Orig string `json:"orig,omitempty"`
+	// Comments are the comments that were removed from the anchor, in order.
+	Comments []string `json:"comments,omitempty"`
+	// Sep is the whitespace that was before the anchor's last comment, if the
+	// comment was at the end of the line.
+	Sep string `json:"sep,omitempty"`
+}
+
+// codeLine is a line of code, without its EOL.
+type codeLine struct {
+	text, eol         string
+	hash, exact       string
+	prev, next        string
+	blank             bool
+	comments, removed []string
+	orig              string
+}
+
+// StripReversible removes comments from the input, like Clean, and returns
+// the code and a sidecar that Restore uses to put the comments back, even if
+// the code has been edited in the meantime. Lines that only had comments are
+// removed from the code, as is the whitespace before a comment at the end of
+// a line; the line structure of the input is otherwise kept. Replace and the
+// whitespace options aren't used.
+func (s *Stripper) StripReversible(input []byte) (code, sidecar []byte, err error) {
+	tokens, err := lexTokens(input, s.profile())
+	if err != nil {
+		return nil, nil, err
+	}
+	var lines []codeLine
+	var entries []sidecarEntry
+	var cur codeLine
+	var before string
+	finish := func(eol string) {
+		cur.eol = eol
+		switch {
+		case len(cur.comments) == 0:
+			if before != "" {
+				entries = append(entries, sidecarEntry{Line: len(lines), Before: before})
+				before = ""
+			}
+			lines = append(lines, cur)
+		case strings.TrimSpace(cur.text) == "":
+			// the line only had comments
+			before += cur.orig + eol
+		default:
+			e := sidecarEntry{Line: len(lines), Before: before, Orig: cur.orig, Comments: cur.comments}
+			if last := cur.removed[len(cur.removed)-1]; strings.TrimSpace(cur.text[len(last):]) == "" {
+				cur.text = strings.TrimRight(last, " \t")
+				e.Sep = last[len(cur.text):]
+			}
+			entries = append(entries, e)
+			before = ""
+			lines = append(lines, cur)
+		}
+		cur = codeLine{}
+	}
+	for _, t := range tokens {
+		if !s.keep(t.typ) {
+			// a line comment's EOL stays in the code
+			c := t.value
+			eol := trailingEOL(c)
+			c = c[:len(c)-len(eol)]
+			cur.orig += c
+			cur.comments = append(cur.comments, c)
+			// the code that precedes the comment on its line
+			cur.removed = append(cur.removed, cur.text)
+			if eol == "" {
+				continue
+			}
+			t.value = eol
+		}
+		for v := t.value; v != ""; {
+			i := strings.IndexByte(v, nl)
+			if i < 0 {
+				cur.text += v
+				cur.orig += v
+				break
+			}
+			text := v[:i]
+			eol := "\n"
+			if strings.HasSuffix(text, "\r") {
+				text, eol = text[:len(text)-1], "\r\n"
+			}
+			cur.text += text
+			cur.orig += text
+			finish(eol)
+			v = v[i+1:]
+		}
+	}
+	if cur.text != "" || cur.orig != "" {
+		finish("")
+	}
+	if before != "" {
+		entries = append(entries, sidecarEntry{Line: len(lines), EOF: true, Before: before})
+	}
+	hashLines(lines)
+	for i, e := range entries {
+		if e.EOF {
+			continue
+		}
+		l := lines[e.Line]
+		entries[i].Hash, entries[i].Exact, entries[i].Prev, entries[i].Next = l.hash, l.exact, l.prev, l.next
+	}
+	for _, l := range lines {
+		code = append(code, l.text...)
+		code = append(code, l.eol...)
+	}
+	sidecar, err = json.Marshal(sidecarFile{Version: sidecarVersion, Entries: entries})
+	return code, sidecar, err
+}
+
+// Restore puts the comments recorded in the sidecar by StripReversible back
+// into the code. Each line that comments were removed from, or before, is
+// found by its hash and the hashes of the lines around it, so the code may
+// have been edited. If such a line is unchanged, it is restored as it was;
+// if it was edited, its comments are put at its end. If it can't be found,
+// e.g. it was removed, its comments are put on lines of their own where it
+// would have been.
+func Restore(code, sidecar []byte) ([]byte, error) {
+	var sc sidecarFile
+	if err := json.Unmarshal(sidecar, &sc); err != nil {
+		return nil, fmt.Errorf("invalid sidecar: %s", err)
+	}
+	if sc.Version != sidecarVersion {
+		return nil, fmt.Errorf("unsupported sidecar version: %d", sc.Version)
+	}
+	lines := splitLines(code)
+	hashLines(lines)
+	index := indexLines(lines)
+	eol := linesEOL(lines)
+	// the entries for each line; the ones for the end of the code are last
+	anchored := make([][]sidecarEntry, len(lines)+1)
+	shift, last := 0, -1
+	for _, e := range sc.Entries {
+		i := len(lines)
+		if !e.EOF {
+			var found bool
+			i, found = findAnchor(index, len(lines), e, last+1, e.Line+shift)
+			if found {
+				shift, last = i-e.Line, i
+			} else {
+				// the comments removed from the anchor get their own lines
+				for _, c := range e.Comments {
+					e.Before += c + eol
+				}
+				e.Comments = nil
+			}
+		}
+		anchored[i] = append(anchored[i], e)
+	}
+	var b []byte
+	for i, es := range anchored {
+		for _, e := range es {
+			if i == len(lines) && len(b) > 0 && b[len(b)-1] != nl {
+				b = append(b, eol...)
+			}
+			b = append(b, e.Before...)
+		}
+		if i == len(lines) {
+			break
+		}
+		b = append(b, restoreLine(lines[i], es)...)
+		b = append(b, lines[i].eol...)
+	}
+	return b, nil
+}
+
+// restoreLine returns the line, l, with the comments of its entries put
+// back.
+func restoreLine(l codeLine, es []sidecarEntry) string {
+	text := l.text
+	restored := false
+	for _, e := range es {
+		if len(e.Comments) == 0 {
+			continue
+		}
+		if !restored && e.Exact == l.exact {
+			text = e.Orig
+			restored = true
+			continue
+		}
+		text = strings.TrimRight(text, " \t")
+		for j, c := range e.Comments {
+			sep := " "
+			if j == len(e.Comments)-1 && e.Sep != "" {
+				sep = e.Sep
+			}
+			text += sep + c
+		}
+	}
+	return text
+}
+
+// lineIndex maps the keys that lines are found by to the indexes of the
+// lines, in order.
+type lineIndex map[string][]int
+
+// indexLines returns the index of the lines, whose hashes are set.
+func indexLines(lines []codeLine) lineIndex {
+	x := lineIndex{}
+	for i, l := range lines {
+		for _, keys := range anchorKeys(l.hash, l.prev, l.next, l.blank) {
+			for _, k := range keys {
+				x[k] = append(x[k], i)
+			}
+		}
+	}
+	return x
+}
+
+// anchorKeys returns the keys of a line, by how well they match: a line
+// matches an anchor with the same key in keys[0] best, with a score of 4,
+// and one with the same key in keys[2] worst, with a score of 2. The hash
+// scores 2, and the hashes of the lines before and after it 1 each; a blank
+// line doesn't match by the lines around it alone.
+func anchorKeys(hash, prev, next string, blank bool) (keys [3][]string) {
+	keys[0] = []string{"hpn " + hash + " " + prev + " " + next}
+	keys[1] = []string{"hp " + hash + " " + prev, "hn " + hash + " " + next}
+	keys[2] = []string{"h " + hash}
+	if !blank {
+		keys[2] = append(keys[2], "pn "+prev+" "+next)
+	}
+	return keys
+}
+
+// findAnchor returns the index of the line that best matches the entry's
+// anchor, starting at from, and whether it was found; if it wasn't, the
+// expected index is returned. A line matches if its hash matches or, if it
+// was edited, the hashes of the lines around it do; a blank line can't be an
+// edited line that wasn't blank. Among the best matches, the closest to the
+// expected index wins.
+func findAnchor(index lineIndex, n int, e sidecarEntry, from, expected int) (int, bool) {
+	for _, keys := range anchorKeys(e.Hash, e.Prev, e.Next, false) {
+		best, bestDist := -1, 0
+		for _, k := range keys {
+			// the closest lines at or after from, either side of expected
+			lines := index[k]
+			j := sort.SearchInts(lines, expected)
+			if expected < from {
+				j = sort.SearchInts(lines, from)
+			}
+			for _, i := range []int{j - 1, j} {
+				if i < 0 || i >= len(lines) || lines[i] < from {
+					continue
+				}
+				dist := lines[i] - expected
+				if dist < 0 {
+					dist = -dist
+				}
+				if best < 0 || dist < bestDist || (dist == bestDist && lines[i] < best) {
+					best, bestDist = lines[i], dist
+				}
+			}
+		}
+		if best >= 0 {
+			return best, true
+		}
+	}
+	if expected < from {
+		expected = from
+	}
+	if expected > n {
+		expected = n
+	}
+	return expected, false
+}
+
+// splitLines splits b into lines.
+func splitLines(b []byte) []codeLine {
+	var lines []codeLine
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, nl)
+		if i < 0 {
+			lines = append(lines, codeLine{text: string(b)})
+			break
+		}
+		l := codeLine{text: string(b[:i]), eol: "\n"}
+		if strings.HasSuffix(l.text, "\r") {
+			l.text, l.eol = l.text[:len(l.text)-1], "\r\n"
+		}
+		lines = append(lines, l)
+		b = b[i+1:]
+	}
+	return lines
+}
+
+// linesEOL returns the EOL the lines use: that of the first line that has
+// one, or \n.
+func linesEOL(lines []codeLine) string {
+	for _, l := range lines {
+		if l.eol != "" {
+			return l.eol
+		}
+	}
+	return "\n"
+}
+
+// hashLines sets the hashes of the lines.
+func hashLines(lines []codeLine) {
+	for i := range lines {
+		fields := strings.Fields(lines[i].text)
+		lines[i].blank = len(fields) == 0
+		lines[i].hash = hashString(strings.Join(fields, " "))
+		lines[i].exact = hashString(lines[i].text)
+	}
+	var prev string
+	for i := range lines {
+		lines[i].prev = prev
+		if !lines[i].blank {
+			prev = lines[i].hash
+		}
+	}
+	var next string
+	for i := len(lines) - 1; i >= 0; i-- {
+		lines[i].next = next
+		if !lines[i].blank {
+			next = lines[i].hash
+		}
+	}
+}
+
+// hashString returns the hash of s, in hex.
+func hashString(s string) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
@@ -0,0 +1,145 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripReversible(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *Profile
+		input   string
+		code    string
+	}{
+		{"empty", Go, "", ""},
+		{"noComments", Go, "a\n\nb\n", "a\n\nb\n"},
+		{"comments", Go, "// a\n\n// b\nc := 1 // d\n\te /* f */ g\n", "\nc := 1\n\te  g\n"},
+		{"block", Go, "a\n\t/* b\n\tc */\nd /* e\n */ f\n", "a\nd  f\n"},
+		{"eof", Go, "a\n// b\n// c", "a\n"},
+		{"onlyComments", Go, "// a\n/* b */", ""},
+		{"crlf", Go, "// a\r\nb // c\r\nd\r\n", "b\r\nd\r\n"},
+		{"kept", Go, "//go:build a\n\n// b\npackage c\n", "//go:build a\n\npackage c\n"},
+		{"shell", Shell, "#!/bin/sh\n# a\necho '# b' # c\n", "#!/bin/sh\necho '# b'\n"},
+		{"default", nil, "a // b\n# c\nd /* e */\n", "a\nd\n"},
+	}
+	for _, test := range tests {
+		s := Stripper{Profile: test.profile, KeepDirectiveComments: true}
+		code, sidecar, err := s.StripReversible([]byte(test.input))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if string(code) != test.code {
+			t.Errorf("%s: got code %q want %q", test.name, code, test.code)
+		}
+		restored, err := Restore(code, sidecar)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if string(restored) != test.input {
+			t.Errorf("%s: got restored %q want %q", test.name, restored, test.input)
+		}
+	}
+}
+
+func TestRestoreEdited(t *testing.T) {
+	input := "// config\na = 1\n\n// the b\nb = 2 # two\nc = 3\n// the end\n"
+	s := Stripper{Profile: HCL}
+	code, sidecar, err := s.StripReversible([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(code) != "a = 1\n\nb = 2\nc = 3\n" {
+		t.Fatalf("got code %q", code)
+	}
+	tests := []struct {
+		name   string
+		code   string
+		output string
+	}{
+		{"inserted", "z = 0\na = 1\n\ny = 0\nb = 2\nc = 3\n", "z = 0\n// config\na = 1\n\ny = 0\n// the b\nb = 2 # two\nc = 3\n// the end\n"},
+		{"whitespace", "a  =  1\n\nb = 2\nc = 3\n", "// config\na  =  1\n\n// the b\nb = 2 # two\nc = 3\n// the end\n"},
+		{"edited", "a = 1\n\nb = 20\nc = 3\n", "// config\na = 1\n\n// the b\nb = 20 # two\nc = 3\n// the end\n"},
+		{"moved", "c = 3\na = 1\n\nb = 2\n", "c = 3\n// config\na = 1\n\n// the b\nb = 2 # two\n// the end\n"},
+		{"removed", "a = 1\n\nc = 3\n", "// config\na = 1\n\n// the b\n# two\nc = 3\n// the end\n"},
+		{"noEOL", "a = 1\n\nb = 2\nc = 3", "// config\na = 1\n\n// the b\nb = 2 # two\nc = 3\n// the end\n"},
+	}
+	for _, test := range tests {
+		restored, err := Restore([]byte(test.code), sidecar)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if string(restored) != test.output {
+			t.Errorf("%s: got %q want %q", test.name, restored, test.output)
+		}
+	}
+}
+
+func TestRestoreRemovedLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		code   string
+		output string
+	}{
+		{"run", "a = 1 # aa\nb = 2 # bb\nc = 3 # cc\nd = 4 # dd\ne = 5 # ee\nf = 6 # ff\n", "a = 1\nb = 2\nc = 3\ne = 5\nf = 6\n", "a = 1 # aa\nb = 2 # bb\nc = 3 # cc\n# dd\ne = 5 # ee\nf = 6 # ff\n"},
+		{"crlf", "a = 1 # aa\r\nb = 2 # bb\r\nc = 3 # cc\r\n", "a = 1\r\nc = 3\r\n", "a = 1 # aa\r\n# bb\r\nc = 3 # cc\r\n"},
+	}
+	s := Stripper{Profile: HCL}
+	for _, test := range tests {
+		_, sidecar, err := s.StripReversible([]byte(test.input))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		restored, err := Restore([]byte(test.code), sidecar)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if string(restored) != test.output {
+			t.Errorf("%s: got %q want %q", test.name, restored, test.output)
+		}
+	}
+}
+
+func TestRestoreRepeatedLines(t *testing.T) {
+	input := strings.Repeat("a = 1 # b\n}\n", 1000)
+	s := Stripper{Profile: HCL}
+	code, sidecar, err := s.StripReversible([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Restore(append([]byte("c = 2\n"), code...), sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "c = 2\n" + input; string(restored) != want {
+		t.Errorf("got %q want %q", restored, want)
+	}
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	for _, sidecar := range []string{"", "{", `{"version":2}`} {
+		_, err := Restore([]byte("a\n"), []byte(sidecar))
+		if err == nil || !strings.Contains(err.Error(), "sidecar") {
+			t.Errorf("%q: got %v, want a sidecar error", sidecar, err)
+		}
+	}
+}
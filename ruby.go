@@ -0,0 +1,314 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Ruby is the profile for Ruby: # line comments and =begin =end block
+// comments, which must start at the beginning of a line. Strings, regular
+// expressions, and generalized quotes, e.g. %q{} and %w[], are not scanned
+// for comments; bracket delimiters nest and #{} interpolations may contain
+// strings of their own. Heredoc bodies and anything after __END__ are passed
+// through as is.
+var Ruby = &Profile{
+	Name:         "ruby",
+	Extensions:   []string{".rb", ".rake", ".gemspec"},
+	Filenames:    []string{"Rakefile", "Gemfile"},
+	Interpreters: []string{"ruby"},
+	Hints:        []string{"def ", "end\n", "require '", "puts ", "do |", "attr_accessor", ".each"},
+	lexText:      lexRuby,
+}
+
+// rubyKeywords are the keywords that may be followed by a regular
+// expression or a generalized quote.
+var rubyKeywords = map[string]bool{
+	"and":    true,
+	"case":   true,
+	"elsif":  true,
+	"if":     true,
+	"in":     true,
+	"not":    true,
+	"or":     true,
+	"puts":   true,
+	"return": true,
+	"unless": true,
+	"until":  true,
+	"when":   true,
+	"while":  true,
+}
+
+// lexRuby lexes Ruby.
+func lexRuby(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '=' && l.atLineStart() && l.atLineWord("=begin"):
+			if !l.lexLineBlock("=end", tokenBlockComment) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '_' && l.atLineStart() && l.atLineWord("__END__"):
+			l.pos = Pos(len(l.input))
+		case c == '#':
+			l.lexLineComment(tokenShellComment)
+		case c == '"' || c == '`' || c == '\'' || (c == '/' && l.regexpAllowed(rubyKeywords)):
+			l.emitText()
+			if !l.skipDelimited(c, c != '\'') {
+				return l.errorf("unterminated quoted string")
+			}
+			if c == '/' {
+				l.skipIdent()
+			}
+			l.emit(tokenQuotedText)
+		case c == '%' && l.atRubyPercentLiteral():
+			l.emitText()
+			l.pos++
+			var interp bool
+			if isIdentByte(l.input[l.pos]) {
+				interp = strings.IndexByte("QWIrx", l.input[l.pos]) >= 0
+				l.pos++
+			} else {
+				interp = true
+			}
+			if !l.skipDelimited(l.input[l.pos], interp) {
+				return l.errorf("unterminated quoted string")
+			}
+			l.skipIdent()
+			l.emit(tokenQuotedText)
+		case c == '?' && l.atRubyChar():
+			if l.hasPrefix("?\\") {
+				l.pos++
+			}
+			l.pos += 2
+		case c == '$' && int(l.pos)+1 < len(l.input) && strings.IndexByte("'\"`#", l.input[l.pos+1]) >= 0:
+			// a special global variable, e.g. $'
+			l.pos += 2
+		case c == '<' && l.hasPrefix("<<"):
+			l.rubyHeredoc()
+		case c == nl:
+			l.pos++
+			if len(l.heredocs) > 0 {
+				l.lexHeredocs()
+			}
+		case isIdentByte(c):
+			// method names may end with ? or !
+			l.skipIdent()
+			if l.hasPrefix("?") || l.hasPrefix("!") {
+				l.pos++
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atLineWord returns whether the line at the current position starts with
+// the word w followed by whitespace or the EOL.
+func (l *lexer) atLineWord(w string) bool {
+	if !l.hasPrefix(w) {
+		return false
+	}
+	i := int(l.pos) + len(w)
+	return i == len(l.input) || isSpace(l.input[i])
+}
+
+// lexLineBlock consumes a block comment whose end delimiter must start a
+// line, through the end of that line, and emits it as typ. If the end
+// delimiter isn't found, false is returned.
+func (l *lexer) lexLineBlock(end string, typ tokenType) bool {
+	l.emitText()
+	for {
+		l.pos = l.lineEnd(l.pos)
+		l.skipEOL()
+		if int(l.pos) >= len(l.input) {
+			return false
+		}
+		if l.atLineWord(end) {
+			l.pos = l.lineEnd(l.pos)
+			l.emit(typ)
+			return true
+		}
+	}
+}
+
+// skipIdent consumes an identifier.
+func (l *lexer) skipIdent() {
+	for int(l.pos) < len(l.input) && isIdentByte(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+// closingDelim returns the delimiter that closes open: the matching bracket
+// or open itself.
+func closingDelim(open byte) byte {
+	if i := strings.IndexByte("([{<", open); i >= 0 {
+		return ")]}>"[i]
+	}
+	return open
+}
+
+// skipDelimited consumes text from the opening delimiter at the current
+// position through its closing delimiter. Bracket delimiters nest, and a
+// backslash escapes the character that follows it. If interp is true, #{}
+// interpolations are code, which may contain strings. If the closing
+// delimiter isn't found, false is returned.
+func (l *lexer) skipDelimited(open byte, interp bool) bool {
+	closing := closingDelim(open)
+	depth := 0
+	for l.pos++; int(l.pos) < len(l.input); l.pos++ {
+		switch c := l.input[l.pos]; {
+		case c == '\\':
+			l.pos++
+		case interp && c == '#' && l.hasPrefix("#{"):
+			l.pos += 2
+			if !l.skipInterpolation() {
+				return false
+			}
+			l.pos--
+		case c == closing && depth == 0:
+			l.pos++
+			return true
+		case c == closing:
+			depth--
+		case c == open:
+			depth++
+		}
+	}
+	return false
+}
+
+// skipInterpolation consumes the code in an interpolation, including the
+// closing }.
+func (l *lexer) skipInterpolation() bool {
+	depth := 0
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; c {
+		case '"', '`', '\'':
+			if !l.skipDelimited(c, c != '\'') {
+				return false
+			}
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				l.pos++
+				return true
+			}
+			depth--
+		}
+		l.pos++
+	}
+	return false
+}
+
+// regexpAllowed returns whether a / at the current position starts a regular
+// expression. That depends on what precedes it: after a value, e.g. an
+// identifier, a number, or a closing bracket, it is division. The keywords
+// are the identifiers after which it is a regular expression.
+func (l *lexer) regexpAllowed(keywords map[string]bool) bool {
+	i := int(l.pos) - 1
+	for i >= 0 && (l.input[i] == ' ' || l.input[i] == '\t') {
+		i--
+	}
+	if i < 0 {
+		return true
+	}
+	switch c := l.input[i]; {
+	case isIdentByte(c):
+		j := i
+		for j >= 0 && isIdentByte(l.input[j]) {
+			j--
+		}
+		return keywords[string(l.input[j+1:i+1])]
+	case c == ')' || c == ']' || c == '}' || c == '"' || c == '\'' || c == '`':
+		return false
+	}
+	return true
+}
+
+// atRubyPercentLiteral returns whether the % at the current position starts
+// a generalized quote, e.g. %q{} or %w[]. Without a type, %(), it is only a
+// quote where a value is expected; otherwise it is modulo.
+func (l *lexer) atRubyPercentLiteral() bool {
+	i := int(l.pos) + 1
+	if i >= len(l.input) {
+		return false
+	}
+	if strings.IndexByte("qQwWiIrsx", l.input[i]) >= 0 {
+		i++
+		return i < len(l.input) && !isIdentByte(l.input[i]) && !isSpace(l.input[i])
+	}
+	c := l.input[i]
+	return !isIdentByte(c) && !isSpace(c) && c != '=' && l.regexpAllowed(rubyKeywords)
+}
+
+// atRubyChar returns whether the ? at the current position starts a
+// character literal, e.g. ?# or ?".
+func (l *lexer) atRubyChar() bool {
+	if l.pos > 0 && (isIdentByte(l.input[l.pos-1]) || l.input[l.pos-1] == ')') {
+		return false
+	}
+	i := int(l.pos) + 1
+	if i < len(l.input) && l.input[i] == '\\' {
+		i++
+	}
+	if i >= len(l.input) || isSpace(l.input[i]) {
+		return false
+	}
+	return i+1 >= len(l.input) || !isIdentByte(l.input[i+1])
+}
+
+// rubyHeredoc processes a heredoc operator, <<ID, <<-ID, or <<~ID; the
+// identifier may be quoted. With - or ~, the terminator may be indented.
+// The body is consumed once the rest of the line has been lexed. If the <<
+// isn't a heredoc operator, it is consumed as is.
+func (l *lexer) rubyHeredoc() {
+	i := int(l.pos) + 2
+	var indent string
+	if i < len(l.input) && (l.input[i] == '-' || l.input[i] == '~') {
+		indent = " \t"
+		i++
+	}
+	delim, n := heredocIdent(l.input[i:])
+	if delim == "" || (indent == "" && l.pos > 0 && isIdentByte(l.input[l.pos-1])) {
+		l.pos += 2
+		return
+	}
+	l.pos = Pos(i + n)
+	l.addHeredoc(delim, indent)
+}
+
+// heredocIdent returns the heredoc identifier at the start of b and the
+// number of bytes it used, including any quotes. If there isn't one, an empty
+// string is returned.
+func heredocIdent(b []byte) (string, int) {
+	if len(b) > 0 && (b[0] == '\'' || b[0] == '"' || b[0] == '`') {
+		i := bytes.IndexByte(b[1:], b[0])
+		if i <= 0 || bytes.IndexByte(b[1:i+1], nl) >= 0 {
+			return "", 0
+		}
+		return string(b[1 : i+1]), i + 2
+	}
+	i := 0
+	for i < len(b) && isIdentByte(b[i]) {
+		i++
+	}
+	if i == 0 || (b[0] >= '0' && b[0] <= '9') {
+		return "", 0
+	}
+	return string(b[:i]), i
+}
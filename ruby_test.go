@@ -0,0 +1,43 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestRuby(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "# a\nputs 1 # b\n", "\nputs 1 \n", ""},
+		{"block", "=begin\na\n=end\nputs 1\n", "\nputs 1\n", ""},
+		{"blockNotLineStart", "x = 1 =begin\n", "x = 1 =begin\n", ""},
+		{"blockEndIndented", "=begin\n =end\n=end # a\nx\n", "\nx\n", ""},
+		{"interpolation", "s = \"#{a # b} \\\" #{ \"}#\" }\" # c\n", "s = \"#{a # b} \\\" #{ \"}#\" }\" \n", ""},
+		{"single", "s = 'a # \\' b' # c\n", "s = 'a # \\' b' \n", ""},
+		{"percent", "a = %q{a {#} b} + %w[# x] + %(#) # c\n", "a = %q{a {#} b} + %w[# x] + %(#) \n", ""},
+		{"modulo", "x = a % (b) # c\n", "x = a % (b) \n", ""},
+		{"regexp", "x =~ /#[a-z]/i # c\n", "x =~ /#[a-z]/i \n", ""},
+		{"division", "x = a / b # c / d\n", "x = a / b \n", ""},
+		{"char", "c = ?# # a\nd = x ? 1 : 2 # b\n", "c = ?# \nd = x ? 1 : 2 \n", ""},
+		{"method", "a.empty? # b\n", "a.empty? \n", ""},
+		{"heredoc", "s = <<~EOS # a\n  # not a comment\n  EOS\n# b\n", "s = <<~EOS \n  # not a comment\n  EOS\n\n", ""},
+		{"heredocQuoted", "s = <<-'EOS'\n# #{x}\nEOS\n", "s = <<-'EOS'\n# #{x}\nEOS\n", ""},
+		{"shift", "x = 1 << 2 # a\nclass << self # b\n", "x = 1 << 2 \nclass << self \n", ""},
+		{"end", "x # a\n__END__\n# data\n", "x \n__END__\n# data\n", ""},
+		{"unclosedBlock", "=begin\na\n", "", "index 0: unclosed block comment"},
+		{"unclosedString", "s = %q{a", "", "index 4: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Ruby}, tests)
+}
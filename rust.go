@@ -0,0 +1,129 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Rust is the profile for Rust: // and /* */ comments; block comments nest.
+// Strings, including byte strings, b"", use backslash escapes; raw strings,
+// r"" and r#""#, may use any number of #. A ' starts a character literal
+// unless it is a lifetime, 'a.
+//
+// Doc comments, ///, //!, /** */ and /*! */, are kept if
+// Stripper.KeepDocComments is set.
+var Rust = &Profile{
+	Name:       "rust",
+	Extensions: []string{".rs"},
+	Hints:      []string{"fn ", "let mut ", "impl ", "pub fn", "use std::", "println!", "&mut "},
+	lexText:    lexRust,
+}
+
+// lexRust lexes Rust.
+func lexRust(l *lexer) stateFn {
+	if l.pos == 0 && l.hasPrefix("#!") && !l.hasPrefix("#![") {
+		l.pos = l.lineEnd(l.pos)
+	}
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '/' && l.hasPrefix("//"):
+			typ := tokenCPPComment
+			if (l.hasPrefix("///") && !l.hasPrefix("////")) || l.hasPrefix("//!") {
+				typ = tokenDocComment
+			}
+			l.lexLineComment(typ)
+		case c == '/' && l.hasPrefix("/*"):
+			typ := tokenCComment
+			if (l.hasPrefix("/**") && !l.hasPrefix("/***") && !l.hasPrefix("/**/")) || l.hasPrefix("/*!") {
+				typ = tokenDocComment
+			}
+			if !l.lexNested("/*", "*/", typ) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '"':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '\'':
+			if !l.atRustChar() {
+				// a lifetime or label
+				l.pos++
+				continue
+			}
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated character literal")
+			}
+		case isIdentByte(c):
+			i := l.pos
+			for int(l.pos) < len(l.input) && isIdentByte(l.input[l.pos]) {
+				l.pos++
+			}
+			switch string(l.input[i:l.pos]) {
+			case "r", "br", "cr":
+			default:
+				continue
+			}
+			hashes := 0
+			for int(l.pos)+hashes < len(l.input) && l.input[int(l.pos)+hashes] == '#' {
+				hashes++
+			}
+			if int(l.pos)+hashes >= len(l.input) || l.input[int(l.pos)+hashes] != '"' {
+				// e.g. a raw identifier, r#type
+				continue
+			}
+			n := int(l.pos-i) + hashes
+			l.pos = i
+			if !l.lexRustRawString(n) {
+				return l.errorf("unterminated raw string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atRustChar returns whether the ' at the current position starts a
+// character literal rather than a lifetime: either an escape or a single
+// character follows it, and then a closing '.
+func (l *lexer) atRustChar() bool {
+	i := int(l.pos) + 1
+	if i >= len(l.input) {
+		return false
+	}
+	if l.input[i] == '\\' {
+		return true
+	}
+	_, w := utf8.DecodeRune(l.input[i:])
+	return i+w < len(l.input) && l.input[i+w] == '\''
+}
+
+// lexRustRawString consumes a raw string, including its prefix, and emits it
+// as quoted text. The opening quote is n bytes from the current position;
+// the bytes before it are the prefix and any #. If the string isn't
+// terminated, false is returned.
+func (l *lexer) lexRustRawString(n int) bool {
+	l.emitText()
+	hashes := bytes.Count(l.input[l.pos:int(l.pos)+n], []byte("#"))
+	end := append([]byte{'"'}, bytes.Repeat([]byte("#"), hashes)...)
+	i := bytes.Index(l.input[int(l.pos)+n+1:], end)
+	if i < 0 {
+		return false
+	}
+	l.pos += Pos(n + 1 + i + len(end))
+	l.emit(tokenQuotedText)
+	return true
+}
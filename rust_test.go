@@ -0,0 +1,48 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestRust(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "let a = 1; // a\n/* b */let b = 2;", "let a = 1; \nlet b = 2;", ""},
+		{"nested", "/* a /* b */ c */fn main() {}", "fn main() {}", ""},
+		{"doc", "/// a\n//! b\n/** c */\n/*! d */\nfn f() {}", "\n\n\n\nfn f() {}", ""},
+		{"notDoc", "//// a\n/**/ /*** b */fn f() {}", "\n fn f() {}", ""},
+		{"string", "let s = \"// a \\\" /* b\"; // c\n", "let s = \"// a \\\" /* b\"; \n", ""},
+		{"byteString", "let s = b\"//\"; // a\n", "let s = b\"//\"; \n", ""},
+		{"raw", "let s = r\"c:\\// a\"; // b\n", "let s = r\"c:\\// a\"; \n", ""},
+		{"rawHashes", "let s = r##\"a \"# // b\"##; // c\n", "let s = r##\"a \"# // b\"##; \n", ""},
+		{"rawByte", "let s = br#\"/* a\"#; /* b */", "let s = br#\"/* a\"#; ", ""},
+		{"rawIdentifier", "let r#type = 1; // a\n", "let r#type = 1; \n", ""},
+		{"char", "let c = '\"'; let d = '\\''; // a\n", "let c = '\"'; let d = '\\''; \n", ""},
+		{"charUnicode", "let c = 'é'; // a\n", "let c = 'é'; \n", ""},
+		{"lifetime", "fn f<'a>(s: &'a str) -> &'static str { \"'\" } // a\n", "fn f<'a>(s: &'a str) -> &'static str { \"'\" } \n", ""},
+		{"label", "'outer: loop { break 'outer; } // a\n", "'outer: loop { break 'outer; } \n", ""},
+		{"byteChar", "let c = b'\"'; // a\n", "let c = b'\"'; \n", ""},
+		{"shebang", "#!/usr/bin/env rust-script\n#![allow(unused)] // a\n", "#!/usr/bin/env rust-script\n#![allow(unused)] \n", ""},
+		{"unclosedNested", "/* a /* b */", "", "index 0: unclosed block comment"},
+		{"unclosedRaw", "let s = r#\"a\";", "", "index 8: unterminated raw string"},
+	}
+	testProfile(t, Stripper{Profile: Rust}, tests)
+
+	keep := []profileTest{
+		{"doc", "/// a\n// b\n/*! c */ /* d */fn f() {}", "/// a\n\n/*! c */ fn f() {}", ""},
+	}
+	testProfile(t, Stripper{Profile: Rust, KeepDocComments: true}, keep)
+}
@@ -0,0 +1,227 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Shell is the profile for POSIX shell and bash scripts.
+//
+// A # only starts a comment when it is at the start of a word, so $# and
+// ${#var} are not comments. Single quoted strings are literal, $'...' strings
+// may contain escaped quotes, command substitutions in double quoted strings
+// may contain quotes of their own, and heredoc bodies are passed through as
+// is. A #! line at the start of the input is not a comment.
+var Shell = &Profile{
+	Name:         "shell",
+	Aliases:      []string{"sh", "bash", "zsh", "shell-script"},
+	Extensions:   []string{".sh", ".bash", ".ksh", ".zsh"},
+	Filenames:    []string{".bashrc", ".bash_profile", ".profile", ".zshrc"},
+	Interpreters: []string{"sh", "bash", "dash", "ksh", "zsh", "ash"},
+	Hints:        []string{"echo ", "fi\n", "then\n", "$(", "${", "done\n", "esac", "export "},
+	lexText:      lexShell,
+}
+
+// shellMeta are the characters, other than whitespace, that separate words.
+const shellMeta = ";&|()<>"
+
+// lexShell lexes shell scripts.
+func lexShell(l *lexer) stateFn {
+	if l.pos == 0 && l.hasPrefix("#!") {
+		l.pos = l.lineEnd(l.pos)
+	}
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '#' && l.atShellWordStart():
+			l.lexLineComment(tokenShellComment)
+		case c == '\\':
+			// whatever follows is escaped, including an EOL
+			l.pos++
+			if int(l.pos) < len(l.input) {
+				l.pos++
+			}
+		case c == '\'':
+			if !l.lexQuoted('\'', 0, false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '$' && l.hasPrefix("$'"):
+			l.pos++
+			if !l.lexQuoted('\'', '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '"':
+			l.emitText()
+			if !l.skipShellDouble() {
+				return l.errorf("unterminated quoted string")
+			}
+			l.emit(tokenQuotedText)
+		case c == '`':
+			if !l.lexQuoted(c, '\\', false) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '(' && l.hasPrefix("(("):
+			if !l.skipShellArith() {
+				l.pos++
+			}
+		case c == '<' && l.hasPrefix("<<") && !l.hasPrefix("<<<"):
+			l.shellHeredoc()
+		case c == nl:
+			l.pos++
+			if len(l.heredocs) > 0 {
+				l.lexHeredocs()
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atShellWordStart returns whether the current position is the start of a
+// shell word.
+func (l *lexer) atShellWordStart() bool {
+	if l.pos == 0 {
+		return true
+	}
+	c := l.input[l.pos-1]
+	return c == ' ' || c == '\t' || c == nl || c == cr || strings.IndexByte(shellMeta, c) >= 0
+}
+
+// skipShellArith consumes an arithmetic expression, (( )) or $(( )), so that
+// the << shift operator isn't mistaken for a heredoc. If the (( isn't closed
+// by )), e.g. ((cd a); ls), it opens nested subshells instead: nothing is
+// consumed and false is returned.
+func (l *lexer) skipShellArith() bool {
+	depth := 0
+	for i := l.pos + 2; int(i) < len(l.input); i++ {
+		switch l.input[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if int(i)+1 < len(l.input) && l.input[i+1] == ')' {
+				l.pos = i + 2
+				return true
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// skipShellDouble consumes a double quoted string. A backslash escapes the
+// character that follows it, and command substitutions, $( ), are code that
+// may contain quotes of their own. If the closing quote isn't found, false is
+// returned.
+func (l *lexer) skipShellDouble() bool {
+	for l.pos++; int(l.pos) < len(l.input); {
+		switch c := l.input[l.pos]; {
+		case c == '\\':
+			l.pos += 2
+		case c == '"':
+			l.pos++
+			return true
+		case c == '$' && l.hasPrefix("$("):
+			l.pos += 2
+			if !l.skipShellSubst() {
+				return false
+			}
+		default:
+			l.pos++
+		}
+	}
+	return false
+}
+
+// skipShellSubst consumes the code in a command substitution, including the
+// closing ).
+func (l *lexer) skipShellSubst() bool {
+	depth := 0
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; c {
+		case '\\':
+			l.pos++
+		case '\'', '`':
+			i := bytes.IndexByte(l.input[l.pos+1:], c)
+			if i < 0 {
+				return false
+			}
+			l.pos += Pos(i) + 1
+		case '"':
+			if !l.skipShellDouble() {
+				return false
+			}
+			continue
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				l.pos++
+				return true
+			}
+			depth--
+		}
+		l.pos++
+	}
+	return false
+}
+
+// shellHeredoc processes a heredoc operator: <<word or <<-word. Any part of
+// word may be quoted; the quotes are not part of the delimiter. The body is
+// consumed once the rest of the line has been lexed.
+func (l *lexer) shellHeredoc() {
+	l.pos += 2
+	var indent string
+	if l.hasPrefix("-") {
+		indent = "\t"
+		l.pos++
+	}
+	for l.hasPrefix(" ") || l.hasPrefix("\t") {
+		l.pos++
+	}
+	var delim []byte
+	var quote byte
+Loop:
+	for int(l.pos) < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				delim = append(delim, c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '\\':
+			if int(l.pos)+1 < len(l.input) {
+				l.pos++
+				delim = append(delim, l.input[l.pos])
+			}
+		case c == ' ' || c == '\t' || c == nl || c == cr || strings.IndexByte(shellMeta, c) >= 0:
+			break Loop
+		default:
+			delim = append(delim, c)
+		}
+		l.pos++
+	}
+	if len(delim) > 0 {
+		l.addHeredoc(string(delim), indent)
+	}
+}
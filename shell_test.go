@@ -0,0 +1,50 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestShell(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "# comment\necho hello # world\n", "\necho hello \n", ""},
+		{"shebang", "#!/bin/bash\n# comment\nls\n", "#!/bin/bash\n\nls\n", ""},
+		{"argCount", "echo $# ${#arr[@]} ${var#*/} # count\n", "echo $# ${#arr[@]} ${var#*/} \n", ""},
+		{"afterMeta", "ls;# comment\n(# comment\n)\n", "ls;\n(\n)\n", ""},
+		{"escaped", "echo \\# not a comment\n", "echo \\# not a comment\n", ""},
+		{"single", "echo 'it''s # not' # is\n", "echo 'it''s # not' \n", ""},
+		{"singleBackslash", "echo 'c:\\' # is\n", "echo 'c:\\' \n", ""},
+		{"ansi", "echo $'don\\'t # stop' # is\n", "echo $'don\\'t # stop' \n", ""},
+		{"double", "echo \"a \\\" # b\" # c\n", "echo \"a \\\" # b\" \n", ""},
+		{"backtick", "echo `echo '#'` # c\n", "echo `echo '#'` \n", ""},
+		{"heredoc", "cat <<EOF # c\n# body\nEOF\n# c\n", "cat <<EOF \n# body\nEOF\n\n", ""},
+		{"heredocDash", "cat <<-EOF\n\t# body\n\tEOF\n# c\n", "cat <<-EOF\n\t# body\n\tEOF\n\n", ""},
+		{"heredocQuoted", "cat << 'EOF'\n# $body\nEOF\n", "cat << 'EOF'\n# $body\nEOF\n", ""},
+		{"heredocDoubleQuoted", "cat <<\"E F\"\n# body\nE F\n# c\n", "cat <<\"E F\"\n# body\nE F\n\n", ""},
+		{"heredocs", "cat <<A <<B\n# a\nA\n# b\nB\n# c\n", "cat <<A <<B\n# a\nA\n# b\nB\n\n", ""},
+		{"heredocUnterminated", "cat <<EOF\n# body\n", "cat <<EOF\n# body\n", ""},
+		{"hereString", "cat <<< foo # c\n", "cat <<< foo \n", ""},
+		{"arithmetic", "echo $((1<<2)) # c\n# c\n", "echo $((1<<2)) \n\n", ""},
+		{"subshells", "((cd a); ls) # c\n", "((cd a); ls) \n", ""},
+		{"subshellsHeredoc", "((cat <<EOF\n# body\nEOF\n) )\n", "((cat <<EOF\n# body\nEOF\n) )\n", ""},
+		{"doubleSubst", "echo \"$(echo \")\")\" # c\n", "echo \"$(echo \")\")\" \n", ""},
+		{"doubleSubstNested", "echo \"$(a \"$(b ')')\" (c))#\" # d\n", "echo \"$(a \"$(b ')')\" (c))#\" \n", ""},
+		{"crlf", "ls # c\r\nls\r\n", "ls \r\nls\r\n", ""},
+		{"unclosedSingle", "echo 'abc", "", "index 5: unterminated quoted string"},
+		{"unclosedDouble", "echo \"abc", "", "index 5: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: Shell}, tests)
+}
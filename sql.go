@@ -0,0 +1,156 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+// SQL dialect flags.
+const (
+	sqlMySQL profileFlag = 1 << iota
+	sqlPostgreSQL
+)
+
+// SQL is the profile for standard SQL: -- line comments and /* */ block
+// comments. Strings are delimited by ' and identifiers by "; a quote is
+// escaped by doubling it.
+var SQL = &Profile{
+	Name:       "sql",
+	Extensions: []string{".sql"},
+	Hints:      []string{"SELECT ", "FROM ", "WHERE ", "INSERT INTO", "CREATE TABLE", "select ", "from ", "where "},
+	lexText:    lexSQL,
+}
+
+// MySQL is the SQL profile with MySQL's extensions: # line comments, -- must
+// be followed by whitespace to start a comment, backtick quoted identifiers,
+// and backslash escapes in strings. Executable comments, /*! */, are code, so
+// they are not elided.
+var MySQL = &Profile{
+	Name:    "mysql",
+	Hints:   []string{"AUTO_INCREMENT", "ENGINE=", "UNSIGNED", "LIMIT ", "`"},
+	lexText: lexSQL,
+	flags:   sqlMySQL,
+}
+
+// PostgreSQL is the SQL profile with PostgreSQL's extensions: block comments
+// nest, E'...' strings have backslash escapes, and dollar quoted strings, $$ $$
+// and $tag$ $tag$, are never scanned for comments.
+var PostgreSQL = &Profile{
+	Name:       "postgresql",
+	Aliases:    []string{"pgsql", "postgres", "plpgsql"},
+	Extensions: []string{".pgsql", ".psql"},
+	Hints:      []string{"$$", "SERIAL", "::", "RETURNING ", "plpgsql", "CREATE OR REPLACE FUNCTION"},
+	lexText:    lexSQL,
+	flags:      sqlPostgreSQL,
+}
+
+// lexSQL lexes SQL using the dialect rules of the lexer's profile.
+func lexSQL(l *lexer) stateFn {
+	mysql := l.profile.has(sqlMySQL)
+	postgres := l.profile.has(sqlPostgreSQL)
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '-' && l.hasPrefix("--"):
+			if mysql && !l.atMySQLDashComment() {
+				l.pos += 2
+				continue
+			}
+			l.lexLineComment(tokenLineComment)
+		case c == '#' && mysql:
+			l.lexLineComment(tokenShellComment)
+		case c == '/' && mysql && l.hasPrefix("/*!"):
+			// executable comment: it's code
+			l.emitText()
+			if !l.lexDelimited("/*!", "*/", tokenText) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '/' && l.hasPrefix("/*"):
+			var ok bool
+			if postgres {
+				ok = l.lexNested("/*", "*/", tokenCComment)
+			} else {
+				ok = l.lexDelimited("/*", "*/", tokenCComment)
+			}
+			if !ok {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '\'':
+			var escape byte
+			if mysql || (postgres && l.atPostgresEscapeString()) {
+				escape = '\\'
+			}
+			if !l.lexQuoted(c, escape, true) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '"':
+			var escape byte
+			if mysql {
+				escape = '\\'
+			}
+			if !l.lexQuoted(c, escape, true) {
+				return l.errorf("unterminated quoted identifier")
+			}
+		case c == '`' && mysql:
+			if !l.lexQuoted(c, 0, true) {
+				return l.errorf("unterminated quoted identifier")
+			}
+		case c == '$' && postgres:
+			tag := l.dollarQuoteTag()
+			if tag == "" {
+				l.pos++
+				continue
+			}
+			if !l.lexDelimited(tag, tag, tokenQuotedText) {
+				return l.errorf("unterminated dollar quoted string")
+			}
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atMySQLDashComment returns whether the -- at the current position starts a
+// MySQL comment: it must be followed by whitespace, a control character, or
+// EOF.
+func (l *lexer) atMySQLDashComment() bool {
+	i := int(l.pos) + 2
+	return i >= len(l.input) || l.input[i] <= ' '
+}
+
+// atPostgresEscapeString returns whether the ' at the current position starts
+// an escape string constant, E'...', which uses backslash escapes.
+func (l *lexer) atPostgresEscapeString() bool {
+	if l.pos == 0 || (l.input[l.pos-1] != 'E' && l.input[l.pos-1] != 'e') {
+		return false
+	}
+	return l.pos == 1 || !isIdentByte(l.input[l.pos-2])
+}
+
+// dollarQuoteTag returns the PostgreSQL dollar quote tag, e.g. $$ or
+// $body$, at the current position. If there isn't one, an empty string is
+// returned. Identifiers may contain $, so the tag can't follow one; neither
+// can it start with a digit: $1 is a parameter.
+func (l *lexer) dollarQuoteTag() string {
+	if l.pos > 0 && isIdentByte(l.input[l.pos-1]) {
+		return ""
+	}
+	for i := int(l.pos) + 1; i < len(l.input); i++ {
+		c := l.input[i]
+		if c == '$' {
+			return string(l.input[l.pos : i+1])
+		}
+		if !isIdentByte(c) || (i == int(l.pos)+1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"line", "-- comment\nSELECT 1; -- one\n", "\nSELECT 1; \n", ""},
+		{"block", "SELECT /* a\nb */ 1;", "SELECT  1;", ""},
+		{"notNested", "/* a /* b */ SELECT 1;", " SELECT 1;", ""},
+		{"string", "SELECT '-- not', 'it''s /* not */' -- c\n", "SELECT '-- not', 'it''s /* not */' \n", ""},
+		{"backslash", "SELECT 'c:\\' -- c\n", "SELECT 'c:\\' \n", ""},
+		{"identifier", "SELECT \"a--b\" FROM \"x\"\"/*\" -- c\n", "SELECT \"a--b\" FROM \"x\"\"/*\" \n", ""},
+		{"hash", "SELECT 1 # not a comment\n", "SELECT 1 # not a comment\n", ""},
+		{"unclosedBlock", "SELECT 1 /* a", "", "index 9: unclosed block comment"},
+		{"unclosedString", "SELECT 'a", "", "index 7: unterminated quoted string"},
+		{"unclosedIdentifier", "SELECT \"a", "", "index 7: unterminated quoted identifier"},
+	}
+	testProfile(t, Stripper{Profile: SQL}, tests)
+}
+
+func TestMySQL(t *testing.T) {
+	tests := []profileTest{
+		{"hash", "SELECT 1; # comment\n", "SELECT 1; \n", ""},
+		{"dash", "SELECT 1 -- comment\n", "SELECT 1 \n", ""},
+		{"dashEOF", "SELECT 1 --", "SELECT 1 ", ""},
+		{"dashNoSpace", "SELECT 1--1;\n", "SELECT 1--1;\n", ""},
+		{"backtick", "SELECT `a#b` FROM `x``--y` # c\n", "SELECT `a#b` FROM `x``--y` \n", ""},
+		{"backslash", "SELECT 'it\\'s # not' # c\n", "SELECT 'it\\'s # not' \n", ""},
+		{"doubled", "SELECT \"say \"\"#\"\"\" # c\n", "SELECT \"say \"\"#\"\"\" \n", ""},
+		{"executable", "/*!40101 SET NAMES utf8 */; /* c */", "/*!40101 SET NAMES utf8 */; ", ""},
+		{"unclosedBacktick", "SELECT `a", "", "index 7: unterminated quoted identifier"},
+	}
+	testProfile(t, Stripper{Profile: MySQL}, tests)
+}
+
+func TestPostgreSQL(t *testing.T) {
+	tests := []profileTest{
+		{"nested", "/* a /* b */ c */SELECT 1;", "SELECT 1;", ""},
+		{"dollar", "SELECT $$ -- not /* not */ $$; -- c\n", "SELECT $$ -- not /* not */ $$; \n", ""},
+		{"dollarTag", "AS $fn$ BEGIN -- keep\n $$ x $$ END $fn$; -- c\n", "AS $fn$ BEGIN -- keep\n $$ x $$ END $fn$; \n", ""},
+		{"param", "SELECT $1 -- c\n", "SELECT $1 \n", ""},
+		{"identDollar", "SELECT a$b$ -- c\n", "SELECT a$b$ \n", ""},
+		{"escapeString", "SELECT E'it\\'s -- not' -- c\n", "SELECT E'it\\'s -- not' \n", ""},
+		{"standardString", "SELECT 'c:\\' -- c\n", "SELECT 'c:\\' \n", ""},
+		{"hash", "SELECT 1 # not a comment\n", "SELECT 1 # not a comment\n", ""},
+		{"unclosedNested", "/* a /* b */", "", "index 0: unclosed block comment"},
+		{"unclosedDollar", "SELECT $x$ a", "", "index 7: unterminated dollar quoted string"},
+	}
+	testProfile(t, Stripper{Profile: PostgreSQL}, tests)
+}
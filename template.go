@@ -0,0 +1,222 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// Template profiles only elide template comments; the text the template
+// produces is left alone. When a comment has a trim marker, the whitespace
+// the marker trims is elided with the comment so that the template renders
+// the same.
+
+// GoTemplate is the profile for Go's text/template and html/template:
+// {{/* */}} comments. Trim markers, {{- /* */ -}}, are honored; as in Go,
+// any whitespace may separate them from the comment.
+var GoTemplate = &Profile{
+	Name:       "gotemplate",
+	Aliases:    []string{"gotmpl"},
+	Extensions: []string{".tmpl", ".gotmpl"},
+	Hints:      []string{"{{ .", "{{.", "{{- ", "{{end}}", "{{ end }}", "{{range", "{{ range"},
+	lexText:    lexGoTemplate,
+}
+
+// Jinja is the profile for Jinja: {# #} comments. Trim markers, {#- -#},
+// are honored. The content of raw blocks, {% raw %}{% endraw %}, is left
+// alone.
+var Jinja = &Profile{
+	Name:       "jinja",
+	Aliases:    []string{"jinja2"},
+	Extensions: []string{".j2", ".jinja", ".jinja2"},
+	Hints:      []string{"{% if", "{% for", "{% endfor", "{% block", "{% endblock", "{% extends", "{{ "},
+	lexText:    lexJinja,
+}
+
+// Handlebars is the profile for Handlebars: {{! }} and {{!-- --}} comments;
+// only the latter may contain }}. Whitespace control, {{~! ~}}, is honored.
+// Escaped mustaches, \{{, and the content of raw blocks, {{{{raw}}}}
+// {{{{/raw}}}}, are left alone.
+var Handlebars = &Profile{
+	Name:       "handlebars",
+	Aliases:    []string{"hbs"},
+	Extensions: []string{".hbs", ".handlebars"},
+	Hints:      []string{"{{#if", "{{/if}}", "{{#each", "{{/each}}", "{{> ", "{{{"},
+	lexText:    lexHandlebars,
+}
+
+var (
+	jinjaRaw    = regexp.MustCompile(`^\{%[-+]?\s*raw\s*[-+]?%\}`)
+	jinjaEndRaw = regexp.MustCompile(`\{%[-+]?\s*endraw\s*[-+]?%\}`)
+)
+
+// lexGoTemplate lexes Go templates.
+func lexGoTemplate(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		begin := 0 // the length of the comment's begin delimiter
+		switch {
+		case l.hasPrefix("{{/*"):
+			begin = len("{{/*")
+		case l.hasPrefix("{{-"):
+			// a trim marker is followed by whitespace
+			n := 3 + leadingSpace(l.input[l.pos+3:])
+			if n > 3 && bytes.HasPrefix(l.input[int(l.pos)+n:], []byte("/*")) {
+				begin = n + 2
+			}
+		}
+		if begin == 0 {
+			l.pos++
+			continue
+		}
+		i := bytes.Index(l.input[int(l.pos)+begin:], []byte("*/"))
+		if i < 0 {
+			l.emitText()
+			return l.errorf("unclosed comment")
+		}
+		end := l.pos + Pos(begin+i+len("*/"))
+		var trim bool
+		switch n := leadingSpace(l.input[end:]); {
+		case bytes.HasPrefix(l.input[end:], []byte("}}")):
+			end += 2
+		case n > 0 && bytes.HasPrefix(l.input[int(end)+n:], []byte("-}}")):
+			end += Pos(n + len("-}}"))
+			trim = true
+		default:
+			l.emitText()
+			return l.errorf("comment ends before closing delimiter")
+		}
+		l.lexTrimmedComment(end, l.input[l.pos+2] == '-', trim)
+	}
+	return lexEOF
+}
+
+// leadingSpace returns the length of the whitespace that b starts with.
+func leadingSpace(b []byte) int {
+	n := 0
+	for n < len(b) && isSpace(b[n]) {
+		n++
+	}
+	return n
+}
+
+// lexJinja lexes Jinja templates.
+func lexJinja(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch {
+		case l.hasPrefix("{#"):
+			i := bytes.Index(l.input[l.pos+2:], []byte("#}"))
+			if i < 0 {
+				l.emitText()
+				return l.errorf("unclosed comment")
+			}
+			end := l.pos + Pos(2+i+2)
+			trim := i > 0 && l.input[end-3] == '-' && (i > 1 || !l.hasPrefix("{#-"))
+			l.lexTrimmedComment(end, l.hasPrefix("{#-"), trim)
+		case l.hasPrefix("{%"):
+			m := jinjaRaw.Find(l.input[l.pos:])
+			if m == nil {
+				l.pos += 2
+				continue
+			}
+			l.emitText()
+			l.pos += Pos(len(m))
+			loc := jinjaEndRaw.FindIndex(l.input[l.pos:])
+			if loc == nil {
+				return l.errorf("unclosed raw block")
+			}
+			l.pos += Pos(loc[1])
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// lexHandlebars lexes Handlebars templates.
+func lexHandlebars(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch {
+		case l.hasPrefix("\\{{"):
+			// an escaped mustache isn't processed
+			i := bytes.Index(l.input[l.pos:], []byte("}}"))
+			if i < 0 {
+				l.pos = Pos(len(l.input))
+				continue
+			}
+			l.pos += Pos(i + 2)
+		case l.hasPrefix("{{{{") && !l.hasPrefix("{{{{/"):
+			l.emitText()
+			i := bytes.Index(l.input[l.pos:], []byte("{{{{/"))
+			if i < 0 {
+				return l.errorf("unclosed raw block")
+			}
+			l.pos += Pos(i)
+			i = bytes.Index(l.input[l.pos:], []byte("}}}}"))
+			if i < 0 {
+				return l.errorf("unclosed raw block")
+			}
+			l.pos += Pos(i + 4)
+		case l.hasPrefix("{{!") || l.hasPrefix("{{~!"):
+			trimLeft := l.hasPrefix("{{~")
+			body := l.pos + 3
+			if trimLeft {
+				body++
+			}
+			closing := "}}"
+			if bytes.HasPrefix(l.input[body:], []byte("--")) {
+				closing = "--}}"
+				body += 2
+			}
+			i := bytes.Index(l.input[body:], []byte(closing))
+			j := bytes.Index(l.input[body:], []byte(closing[:len(closing)-2]+"~}}"))
+			if i < 0 && j < 0 {
+				l.emitText()
+				return l.errorf("unclosed comment")
+			}
+			end, trim := body+Pos(i+len(closing)), false
+			if i < 0 || (j >= 0 && j < i) {
+				end, trim = body+Pos(j+len(closing)+1), true
+			}
+			l.lexTrimmedComment(end, trimLeft, trim)
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// lexTrimmedComment emits the comment from the current position to end as
+// a block comment. If trimLeft is true, the whitespace that precedes the
+// comment is part of it; if trimRight is true, the whitespace that follows
+// it is.
+func (l *lexer) lexTrimmedComment(end Pos, trimLeft, trimRight bool) {
+	if trimLeft {
+		pos := l.pos
+		for l.pos > l.start && isSpace(l.input[l.pos-1]) {
+			l.pos--
+		}
+		l.emitText()
+		l.pos = pos
+	} else {
+		l.emitText()
+	}
+	l.pos = end
+	if trimRight {
+		for int(l.pos) < len(l.input) && isSpace(l.input[l.pos]) {
+			l.pos++
+		}
+	}
+	l.emit(tokenBlockComment)
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestGoTemplate(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "a {{/* b\n c */}} d", "a  d", ""},
+		{"host", "# a\n// b\n{{ .C }} /* d */", "# a\n// b\n{{ .C }} /* d */", ""},
+		{"trimLeft", "a \n\t{{- /* b */}} c", "a c", ""},
+		{"trimRight", "a {{/* b */ -}} \n c", "a c", ""},
+		{"trim", "a\n  {{- /* b */ -}}\n  c", "ac", ""},
+		{"notTrim", "a {{-/* b */}}", "a {{-/* b */}}", ""},
+		{"trimTab", "a \n{{-\t/* b */\t-}}\n c", "ac", ""},
+		{"trimNewline", "a {{-\n/* b */\n\n-}} c", "ac", ""},
+		{"unclosed", "a {{/* b", "", "index 2: unclosed comment"},
+		{"badClose", "a {{/* b */ }}", "", "index 2: comment ends before closing delimiter"},
+	}
+	testProfile(t, Stripper{Profile: GoTemplate}, tests)
+}
+
+func TestJinja(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "a {# b\n c #} d", "a  d", ""},
+		{"host", "# a\n{{ b }} <!-- c -->", "# a\n{{ b }} <!-- c -->", ""},
+		{"trim", "a \n {#- b -#}\n c", "ac", ""},
+		{"trimRight", "a {# b -#}\n c", "a c", ""},
+		{"dash", "a {#-#} b", "a b", ""},
+		{"raw", "{% raw %}{# a #}{% endraw %}{# b #}", "{% raw %}{# a #}{% endraw %}", ""},
+		{"rawTrim", "{%- raw -%}{# a #}{%- endraw %}", "{%- raw -%}{# a #}{%- endraw %}", ""},
+		{"unclosed", "a {# b", "", "index 2: unclosed comment"},
+		{"unclosedRaw", "{% raw %}{# a #}", "", "index 0: unclosed raw block"},
+	}
+	testProfile(t, Stripper{Profile: Jinja}, tests)
+}
+
+func TestHandlebars(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comment", "a {{! b }} c", "a  c", ""},
+		{"long", "a {{!-- b }} --}} c", "a  c", ""},
+		{"trim", "a \n {{~! b ~}}\n c", "ac", ""},
+		{"trimLong", "a {{~!-- }} --}} c", "a c", ""},
+		{"trimRightLong", "a {{!-- b --~}} c", "a c", ""},
+		{"escaped", "\\{{! a }} {{! b }}", "\\{{! a }} ", ""},
+		{"raw", "{{{{raw}}}}{{! a }}{{{{/raw}}}}{{! b }}", "{{{{raw}}}}{{! a }}{{{{/raw}}}}", ""},
+		{"unclosed", "a {{!-- b }}", "", "index 2: unclosed comment"},
+	}
+	testProfile(t, Stripper{Profile: Handlebars}, tests)
+}
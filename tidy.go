@@ -0,0 +1,92 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+)
+
+// appendRemoved records that the comment, c, was elided at the end of the
+// output, b, and returns the updated positions and output. The position
+// recorded is on the line the comment was removed from. If the comment
+// included its EOL, as line comments do with the default rules, that's the
+// line before the end of the output, unless the comment was all that was on
+// it; then the whitespace before it is dropped too, so it isn't joined to the
+// next line.
+func appendRemoved(removed []int, b []byte, c string) ([]int, []byte) {
+	pos := len(b)
+	if len(c) > 0 && c[len(c)-1] == nl {
+		start := bytes.LastIndexByte(b, nl) + 1
+		if len(bytes.TrimLeft(b[start:], " \t\f\v")) == 0 {
+			return removed, b[:start]
+		}
+		pos--
+	}
+	return append(removed, pos), b
+}
+
+// tidy cleans up the whitespace in the output, b, according to the
+// Stripper's settings; removed are the positions of the lines that comments
+// were removed from, in order. b is tidied in place.
+func (s *Stripper) tidy(b []byte, removed []int) []byte {
+	if !s.TrimTrailingSpace && !s.DropEmptyLines && s.MaxBlankLines <= 0 {
+		return b
+	}
+	out := b[:0]
+	blanks := 0
+	for start, next := 0, 0; start < len(b); start = next {
+		end := bytes.IndexByte(b[start:], nl)
+		if end < 0 {
+			end, next = len(b), len(b)
+		} else {
+			end += start
+			next = end + 1
+		}
+		touched := false
+		for len(removed) > 0 && removed[0] <= end {
+			touched = touched || removed[0] >= start
+			removed = removed[1:]
+		}
+		// the EOL is kept as is
+		eol := end
+		if eol > start && b[eol-1] == cr {
+			eol--
+		}
+		line := b[start:eol]
+		if touched && s.TrimTrailingSpace {
+			line = trimTrailingSpace(line)
+		}
+		blank := len(trimTrailingSpace(line)) == 0
+		if blank && touched && s.DropEmptyLines {
+			continue
+		}
+		if blank {
+			blanks++
+			if s.MaxBlankLines > 0 && blanks > s.MaxBlankLines {
+				continue
+			}
+		} else {
+			blanks = 0
+		}
+		// out never passes start, so this is safe in place
+		out = append(out, line...)
+		out = append(out, b[eol:next]...)
+	}
+	return out
+}
+
+// trimTrailingSpace returns b without its trailing whitespace.
+func trimTrailingSpace(b []byte) []byte {
+	return bytes.TrimRight(b, " \t\f\v")
+}
@@ -0,0 +1,63 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestTidy(t *testing.T) {
+	trim := []profileTest{
+		{"empty", "", "", ""},
+		{"trailing", "a := 1 // b\n\t// c\n\nd\n", "a := 1\n\n\nd\n", ""},
+		{"untouched", "a  \nb // c\n", "a  \nb\n", ""},
+		{"block", "a /* b\nc */  \nd /* e */ f \n", "a\nd  f\n", ""},
+		{"crlf", "a // b\r\nc\r\n", "a\r\nc\r\n", ""},
+		{"noEOL", "a // b", "a", ""},
+	}
+	testProfile(t, Stripper{Profile: Go, TrimTrailingSpace: true}, trim)
+
+	drop := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "a := 1 // b\n\t// c\n\nd\n", "a := 1 \n\nd\n", ""},
+		{"blank", "a\n  \n/* b */ \n\n", "a\n  \n\n", ""},
+		{"block", "a\n/* b\nc */\nd\n", "a\nd\n", ""},
+		{"crlf", "a\r\n// b\r\nc\r\n", "a\r\nc\r\n", ""},
+		{"noEOL", "a\n  // b", "a\n", ""},
+	}
+	testProfile(t, Stripper{Profile: Go, DropEmptyLines: true}, drop)
+
+	both := []profileTest{
+		{"comments", "a := 1 // b\n\t// c\n\nd\n", "a := 1\n\nd\n", ""},
+		{"crlf", "a // b\r\n// c\r\nd\r\n", "a\r\nd\r\n", ""},
+		{"kept", "// a\n//go:build b\n", "//go:build b\n", ""},
+	}
+	testProfile(t, Stripper{Profile: Go, TrimTrailingSpace: true, DropEmptyLines: true, KeepDirectiveComments: true}, both)
+
+	max := []profileTest{
+		{"runs", "a\n\n\n\nb\n  \n\nc\n", "a\n\nb\n  \nc\n", ""},
+		{"leading", "\n\n\na\n", "\na\n", ""},
+		{"dropped", "a\n\n// b\n\nc\n", "a\n\nc\n", ""},
+	}
+	testProfile(t, Stripper{Profile: Go, DropEmptyLines: true, MaxBlankLines: 1}, max)
+
+	// with the default rules, line comments include their EOL
+	legacy := []profileTest{
+		{"line", "a\n// b\nc\n", "a\nc\n", ""},
+		{"trailing", "a /* b */\nc /* d */ // e\nf\n", "a\nc  f\n", ""},
+		{"indented", "a\n    // b\nc\n", "a\nc\n", ""},
+		{"indentedCRLF", "a\r\n\t// b\r\nc\r\n", "a\r\nc\r\n", ""},
+	}
+	testProfile(t, Stripper{TrimTrailingSpace: true, DropEmptyLines: true}, legacy)
+}
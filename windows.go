@@ -0,0 +1,261 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Batch is the profile for Windows batch files, .bat and .cmd: REM comments,
+// which start a statement, and :: comments, which start a line. A statement
+// starts a line, follows @ or (, or follows an &, &&, |, or || operator; the
+// operator is elided with the comment. REM is matched without regard to case.
+// Quoted text and characters escaped with ^ are left alone.
+var Batch = &Profile{
+	Name:       "batch",
+	Aliases:    []string{"dosbatch", "bat"},
+	Extensions: []string{".bat", ".cmd"},
+	Hints:      []string{"@echo off", "%~dp0", "goto ", "setlocal", "%ERRORLEVEL%", "errorlevel"},
+	lexText:    lexBatch,
+}
+
+// PowerShell is the profile for PowerShell: # line comments, which start a
+// token, and <# #> block comments. Strings, here-strings, @" "@ and @' '@, and
+// characters escaped with ` are left alone.
+//
+// #Requires statements are directive comments and comment-based help, a block
+// comment that starts with a help keyword like .SYNOPSIS, is a doc comment.
+var PowerShell = &Profile{
+	Name:         "powershell",
+	Aliases:      []string{"ps1"},
+	Extensions:   []string{".ps1", ".psm1", ".psd1"},
+	Interpreters: []string{"pwsh", "powershell"},
+	Hints:        []string{"param(", "$PSScriptRoot", "Write-Host", "Get-", "-eq ", "$null", "Write-Output"},
+	lexText:      lexPowerShell,
+}
+
+// VBScript is the profile for VBScript: ' line comments and Rem comments,
+// which start a statement. Rem is matched without regard to case. Strings use
+// doubled quote escapes.
+var VBScript = &Profile{
+	Name:       "vbscript",
+	Aliases:    []string{"vb"},
+	Extensions: []string{".vbs"},
+	Hints:      []string{"Dim ", "End Sub", "End If", "WScript.", "Set ", "Then"},
+	lexText:    lexVBScript,
+}
+
+// batchOps are the characters that make up batch command operators.
+const batchOps = "&|"
+
+// lexBatch lexes batch files.
+func lexBatch(l *lexer) stateFn {
+	stmt := true  // whether a statement may start at the current position
+	op := Pos(-1) // the start of the operator that preceded the statement
+	var quoted bool
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == nl || c == cr:
+			l.pos++
+			stmt, op, quoted = true, -1, false
+		case quoted:
+			quoted = c != '"'
+			l.pos++
+		case c == '"':
+			quoted, stmt = true, false
+			l.pos++
+		case c == '^':
+			l.skipEscaped()
+			stmt = false
+		case strings.IndexByte(batchOps, c) >= 0:
+			if !stmt || op < 0 {
+				op = l.pos
+			}
+			l.pos++
+			stmt = true
+		case c == '(':
+			l.pos++
+			stmt, op = true, -1
+		case c == ' ' || c == '\t':
+			l.pos++
+		case c == '@' && stmt:
+			l.pos++
+		case c == ':' && l.hasPrefix("::") && l.atFirstNonSpace():
+			l.lexLineComment(tokenLineComment)
+		case stmt && l.atKeywordFold("rem"):
+			if op >= 0 {
+				l.pos = op
+			}
+			for l.pos > l.start && l.input[l.pos-1] == '@' {
+				l.pos--
+			}
+			l.lexLineComment(tokenLineComment)
+		default:
+			l.pos++
+			stmt, op = false, -1
+		}
+	}
+	return lexEOF
+}
+
+// lexPowerShell lexes PowerShell.
+func lexPowerShell(l *lexer) stateFn {
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == '`':
+			l.skipEscaped()
+		case c == '<' && l.hasPrefix("<#"):
+			typ := tokenBlockComment
+			if l.atCommentBasedHelp() {
+				typ = tokenDocComment
+			}
+			if !l.lexDelimited("<#", "#>", typ) {
+				return l.errorf("unclosed block comment")
+			}
+		case c == '#' && l.atPowerShellTokenStart():
+			typ := tokenShellComment
+			if l.atKeywordFold("#requires") {
+				typ = tokenDirectiveComment
+			}
+			l.lexLineComment(typ)
+		case c == '@' && l.atHereString():
+			if !l.lexHereString() {
+				return l.errorf("unterminated here-string")
+			}
+		case c == '"':
+			if !l.lexQuoted(c, '`', true) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '\'':
+			if !l.lexQuoted(c, 0, true) {
+				return l.errorf("unterminated quoted string")
+			}
+		case c == '$' && l.hasPrefix("${"):
+			// a variable name in braces may contain any character
+			i := bytes.IndexByte(l.input[l.pos:], '}')
+			if i < 0 {
+				l.pos = Pos(len(l.input))
+				continue
+			}
+			l.pos += Pos(i + 1)
+		default:
+			l.pos++
+		}
+	}
+	return lexEOF
+}
+
+// atPowerShellTokenStart returns whether the current position starts a
+// PowerShell token: a # within a bareword, e.g. a#b, isn't a comment.
+func (l *lexer) atPowerShellTokenStart() bool {
+	if l.pos == 0 {
+		return true
+	}
+	c := l.input[l.pos-1]
+	return isSpace(c) || strings.IndexByte(";(){}|&,=", c) >= 0
+}
+
+// atCommentBasedHelp returns whether the block comment at the current
+// position starts with a help keyword, e.g. .SYNOPSIS.
+func (l *lexer) atCommentBasedHelp() bool {
+	i := int(l.pos) + 2
+	for i < len(l.input) && isSpace(l.input[i]) {
+		i++
+	}
+	return i+1 < len(l.input) && l.input[i] == '.' &&
+		((l.input[i+1] >= 'a' && l.input[i+1] <= 'z') || (l.input[i+1] >= 'A' && l.input[i+1] <= 'Z'))
+}
+
+// atHereString returns whether a here-string starts at the current position:
+// @" or @' followed by the EOL.
+func (l *lexer) atHereString() bool {
+	if !l.hasPrefix("@\"") && !l.hasPrefix("@'") {
+		return false
+	}
+	i := int(l.pos) + 2
+	for i < len(l.input) && (l.input[i] == ' ' || l.input[i] == '\t') {
+		i++
+	}
+	return i == len(l.input) || l.input[i] == nl || l.input[i] == cr
+}
+
+// lexHereString consumes a here-string through its terminator, "@ or '@ at
+// the start of a line, and emits it as quoted text. If the terminator isn't
+// found, false is returned.
+func (l *lexer) lexHereString() bool {
+	end := string([]byte{l.input[l.pos+1], '@'})
+	l.emitText()
+	for {
+		l.pos = l.lineEnd(l.pos)
+		l.skipEOL()
+		if int(l.pos) >= len(l.input) {
+			return false
+		}
+		if l.hasPrefix(end) {
+			l.pos += 2
+			l.emit(tokenQuotedText)
+			return true
+		}
+	}
+}
+
+// lexVBScript lexes VBScript.
+func lexVBScript(l *lexer) stateFn {
+	stmt := true // whether a statement may start at the current position
+	for int(l.pos) < len(l.input) {
+		switch c := l.input[l.pos]; {
+		case c == nl || c == ':':
+			l.pos++
+			stmt = true
+		case isSpace(c):
+			l.pos++
+		case c == '\'':
+			l.lexLineComment(tokenLineComment)
+		case c == '"':
+			if !l.lexQuoted(c, 0, true) {
+				return l.errorf("unterminated quoted string")
+			}
+			stmt = false
+		case stmt && l.atKeywordFold("rem"):
+			l.lexLineComment(tokenLineComment)
+		case isIdentByte(c):
+			l.skipIdent()
+			stmt = false
+		default:
+			l.pos++
+			stmt = false
+		}
+	}
+	return lexEOF
+}
+
+// skipEscaped consumes the escape character at the current position and the
+// character it escapes.
+func (l *lexer) skipEscaped() {
+	l.pos++
+	if int(l.pos) < len(l.input) {
+		l.pos++
+	}
+}
+
+// atKeywordFold returns whether the keyword w, matched without regard to
+// case, is at the current position and is followed by whitespace or EOF.
+func (l *lexer) atKeywordFold(w string) bool {
+	i := int(l.pos) + len(w)
+	if i > len(l.input) || !bytes.EqualFold(l.input[l.pos:i], []byte(w)) {
+		return false
+	}
+	return i == len(l.input) || isSpace(l.input[i])
+}
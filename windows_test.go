@@ -0,0 +1,74 @@
+// Copyright 2016 Joel Scoble
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nocomment
+
+import (
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"rem", "REM a\r\necho b\r\nrem\r\n", "\r\necho b\r\n\r\n", ""},
+		{"atRem", "@echo off\n@Rem a\n", "@echo off\n\n", ""},
+		{"label", "  :: a\n:b\ngoto :b\n", "  \n:b\ngoto :b\n", ""},
+		{"colons", "echo a::b\n", "echo a::b\n", ""},
+		{"ampersand", "echo a & rem b\necho c && REM d\n", "echo a \necho c \n", ""},
+		{"parens", "if x (rem a\n  echo b\n)\n", "if x (\n  echo b\n)\n", ""},
+		{"notKeyword", "echo rem a\nremove.exe\n", "echo rem a\nremove.exe\n", ""},
+		{"quoted", "echo \"a & rem b\" & rem c\n", "echo \"a & rem b\" \n", ""},
+		{"escaped", "echo a ^& rem b\n", "echo a ^& rem b\n", ""},
+		{"escapeEOF", "echo ^", "echo ^", ""},
+	}
+	testProfile(t, Stripper{Profile: Batch}, tests)
+}
+
+func TestPowerShell(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "$a = 1 # b\n<# c\n d #>$e = 2;#f\n", "$a = 1 \n$e = 2;\n", ""},
+		{"bareword", "Write-Host a#b # c\n", "Write-Host a#b \n", ""},
+		{"strings", "\"a # b `\" <# c\" + 'd ''#'' e' # f\n", "\"a # b `\" <# c\" + 'd ''#'' e' \n", ""},
+		{"escaped", "echo `# a # b\n", "echo `# a \n", ""},
+		{"hereString", "$a = @\"\n# a\n\"@ # b\n$c = @'\n<# c #>\n'@\n", "$a = @\"\n# a\n\"@ \n$c = @'\n<# c #>\n'@\n", ""},
+		{"braceVariable", "${a#b} = 1 # c\n", "${a#b} = 1 \n", ""},
+		{"help", "<#\n.SYNOPSIS\nA\n#>\n<# b #>\nfunction a {}\n", "\n\nfunction a {}\n", ""},
+		{"unclosedComment", "a <# b", "", "index 2: unclosed block comment"},
+		{"unclosedString", "a 'b", "", "index 2: unterminated quoted string"},
+		{"unclosedHereString", "a @\"\nb\n", "", "index 2: unterminated here-string"},
+	}
+	testProfile(t, Stripper{Profile: PowerShell}, tests)
+
+	keep := []profileTest{
+		{"requires", "#Requires -Version 5\n# a\n", "#Requires -Version 5\n\n", ""},
+	}
+	testProfile(t, Stripper{Profile: PowerShell, KeepDirectiveComments: true}, keep)
+
+	keep = []profileTest{
+		{"help", "<# .SYNOPSIS a #>\n<# b #>\n", "<# .SYNOPSIS a #>\n\n", ""},
+	}
+	testProfile(t, Stripper{Profile: PowerShell, KeepDocComments: true}, keep)
+}
+
+func TestVBScript(t *testing.T) {
+	tests := []profileTest{
+		{"empty", "", "", ""},
+		{"comments", "Dim a ' b\nRem c\n  rem\n", "Dim a \n\n  \n", ""},
+		{"statement", "a = 1 : REM b\n", "a = 1 : \n", ""},
+		{"notKeyword", "Remove a\nx = rem + 1\n", "Remove a\nx = rem + 1\n", ""},
+		{"strings", "a = \"b ' \"\"c\"\" rem\" ' d\n", "a = \"b ' \"\"c\"\" rem\" \n", ""},
+		{"unclosedString", "a = \"b", "", "index 4: unterminated quoted string"},
+	}
+	testProfile(t, Stripper{Profile: VBScript}, tests)
+}